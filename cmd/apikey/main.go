@@ -0,0 +1,136 @@
+// Command apikey manages API keys stored in Postgres:
+//
+//	apikey create -user USER -name NAME -scopes quotes:read,quotes:write [-ttl 720h]
+//	apikey list
+//	apikey revoke ID
+//
+// The created key is printed once, only its hash is stored. Keys act with the
+// role of their user, see the user command.
+package main
+
+import (
+	"app/internal/config"
+	"app/internal/logger"
+	"app/internal/services/apikeys"
+	"app/internal/storage/postgres"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `usage:
+  apikey create -user USER -name NAME -scopes SCOPES [-ttl DURATION]
+  apikey list
+  apikey revoke ID
+
+scopes: ` + apikeys.ScopeRead + `, ` + apikeys.ScopeWrite + `, ` + apikeys.ScopeDelete
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	cfg := config.MustRead()
+
+	logger := logger.New(cfg.Log)
+
+	pg, err := postgres.New(ctx, logger, cfg.DbConnString)
+	if err != nil {
+		logger.Error("failed to create storage", "error", err)
+		os.Exit(1)
+	}
+	defer pg.Close()
+
+	keys := apikeys.New(logger, pg)
+
+	switch os.Args[1] {
+	case "create":
+		err = create(ctx, keys, os.Args[2:])
+	case "list":
+		err = list(ctx, keys)
+	case "revoke":
+		err = revoke(ctx, keys, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func create(ctx context.Context, keys *apikeys.Service, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	user := fs.String("user", "", "user the key acts as")
+	name := fs.String("name", "", "who or what the key is for")
+	scopes := fs.String("scopes", apikeys.ScopeRead, "comma separated scopes")
+	ttl := fs.Duration("ttl", 0, "lifetime of the key, 0 never expires")
+	fs.Parse(args)
+
+	token, key, err := keys.Create(ctx, *user, *name, strings.Split(*scopes, ","), *ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("key %d %q of %s created with scopes %s\n", key.ID, key.Name, *user, strings.Join(key.Scopes, ","))
+	if key.ExpiresAt != nil {
+		fmt.Printf("expires at %s\n", key.ExpiresAt.Format(time.RFC3339))
+	}
+	fmt.Printf("\n%s\n\nstore it now, it cannot be shown again\n", token)
+
+	return nil
+}
+
+func list(ctx context.Context, keys *apikeys.Service) error {
+	list, err := keys.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tNAME\tPREFIX\tSCOPES\tCREATED\tEXPIRES\tREVOKED")
+
+	for _, k := range list {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			k.ID, k.UserID, k.Name, k.Prefix, strings.Join(k.Scopes, ","),
+			k.CreatedAt.Format(time.RFC3339), formatTime(k.ExpiresAt), formatTime(k.RevokedAt))
+	}
+
+	return w.Flush()
+}
+
+func revoke(ctx context.Context, keys *apikeys.Service, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid key id %q", args[0])
+	}
+
+	if err := keys.Revoke(ctx, id); err != nil {
+		return err
+	}
+
+	fmt.Printf("key %d revoked\n", id)
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
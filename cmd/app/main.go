@@ -3,6 +3,11 @@ package main
 import (
 	"app/internal/api"
 	"app/internal/config"
+	"app/internal/lib/jwtauth"
+	"app/internal/lib/tokenbucket"
+	"app/internal/services/purger"
+	"app/internal/storage"
+	"app/internal/storage/memory"
 	"app/internal/storage/postgres"
 	"context"
 	"errors"
@@ -34,25 +39,62 @@ func main() {
 	logger := logger.New(cfg.Log)
 
 	// Initialize storage
-	storage, err := postgres.New(ctx, logger, cfg.DbConnString)
-	if err != nil {
-		logger.Error("failed to create storage", "error", err)
+	var (
+		store storage.Storage
+		pg    *postgres.PostgreStorage
+	)
+
+	switch cfg.StorageDriver {
+	case config.DriverMemory:
+		store = memory.New(logger)
+
+	case config.DriverPostgres:
+		var err error
+
+		pg, err = postgres.New(ctx, logger, cfg.DbConnString)
+		if err != nil {
+			logger.Error("failed to create storage", "error", err)
+			return
+		}
+
+		// start migrations
+		err = startMigrations(logger, cfg.DbConnString)
+		if err != nil {
+			logger.Error("failed to start migrations", "error", err)
+			return
+		}
+
+		store = pg
+
+	default:
+		logger.Error("unknown storage driver", "driver", cfg.StorageDriver)
 		return
 	}
 
-	// start migrations
-	err = startMigrations(logger, cfg.DbConnString)
-	if err != nil {
-		logger.Error("failed to start migrations", "error", err)
-		return
+	// JWTs of the gateway, keys are reloaded when the file changes
+	var jwks *jwtauth.KeySet
+	if cfg.JWKSFile != "" {
+		var err error
+
+		jwks, err = jwtauth.Load(logger, cfg.JWKSFile)
+		if err != nil {
+			logger.Error("failed to load JWKS", "error", err)
+			return
+		}
+	}
+
+	// token buckets of the rate limiter, shared between replicas in Postgres
+	var buckets tokenbucket.Store = tokenbucket.NewMemoryStore()
+	if cfg.RateLimitStore == config.DriverPostgres {
+		buckets = pg
 	}
 
 	// initialize HTTP API
-	API := api.New(storage, logger)
+	API := api.New(store, logger, cfg, jwks, buckets)
 
 	srv := http.Server{
 		Addr:    cfg.ServerHost + ":" + cfg.ServerPort,
-		Handler: &API.Router,
+		Handler: API.Handler(),
 	}
 
 	// Graceful shutdown
@@ -66,18 +108,30 @@ func main() {
 		chanErrors <- srv.ListenAndServe()
 	}()
 
-	go func() {
-		logger.Info("Started to ping databse")
-		for {
-			time.Sleep(5 * time.Second)
-			err := storage.Ping(ctx)
-			if err != nil {
-				chanErrors <- err
-				break
+	if pg != nil {
+		go func() {
+			logger.Info("Started to ping databse")
+			for {
+				time.Sleep(5 * time.Second)
+				err := pg.Ping(ctx)
+				if err != nil {
+					chanErrors <- err
+					break
+				}
 			}
-		}
 
-	}()
+		}()
+	}
+
+	// Purge quotes that stayed in the trash longer than retention
+	purgeCtx, stopPurger := context.WithCancel(ctx)
+	defer stopPurger()
+
+	go purger.New(logger, API.Service, cfg.TrashRetention, cfg.PurgeInterval).Run(purgeCtx)
+
+	if jwks != nil {
+		go jwks.Watch(purgeCtx, cfg.JWKSReload)
+	}
 
 	logger.Info("HTTP server is runned", "addres", srv.Addr)
 
@@ -102,7 +156,9 @@ func main() {
 			}
 		}
 
-		storage.Close()
+		stopPurger()
+
+		store.Close()
 
 		logger.Info(InfoDbClosed)
 
@@ -0,0 +1,90 @@
+// Command dedupe reports quotes that are duplicates of each other by
+// fingerprint (see package fingerprint). With -merge it keeps the oldest quote
+// of every group, moves the others to the trash and fills fingerprints of the
+// quotes stored before fingerprints existed.
+package main
+
+import (
+	"app/internal/config"
+	"app/internal/lib/fingerprint"
+	"app/internal/logger"
+	"app/internal/storage"
+	"app/internal/storage/postgres"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+)
+
+func main() {
+	merge := flag.Bool("merge", false, "merge duplicates instead of only reporting them")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	cfg := config.MustRead()
+
+	logger := logger.New(cfg.Log)
+
+	pg, err := postgres.New(ctx, logger, cfg.DbConnString)
+	if err != nil {
+		logger.Error("failed to create storage", "error", err)
+		os.Exit(1)
+	}
+	defer pg.Close()
+
+	// quote ids by fingerprint, export reads quotes ordered by id
+	groups := make(map[string][]int)
+	var order []string
+
+	err = pg.Export(ctx, storage.ExportFilter{}, func(q *storage.StorageQuote) error {
+		fp := fingerprint.Of(q.Text, q.Author)
+		if _, ok := groups[fp]; !ok {
+			order = append(order, fp)
+		}
+		groups[fp] = append(groups[fp], q.Id)
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to read quotes", "error", err)
+		os.Exit(1)
+	}
+
+	fingerprints := make(map[int]string)
+	duplicates := 0
+
+	for _, fp := range order {
+		ids := groups[fp]
+		slices.Sort(ids)
+
+		if len(ids) == 1 {
+			fingerprints[ids[0]] = fp
+			continue
+		}
+
+		duplicates += len(ids) - 1
+		fmt.Printf("quote %d has duplicates %v\n", ids[0], ids[1:])
+
+		if *merge {
+			if err := pg.MergeDuplicates(ctx, ids[0], ids[1:], fp); err != nil {
+				logger.Error("failed to merge duplicates", "error", err, "id", ids[0])
+				os.Exit(1)
+			}
+		}
+	}
+
+	fmt.Printf("%d quotes, %d duplicates\n", len(order)+duplicates, duplicates)
+
+	if !*merge {
+		return
+	}
+
+	// unique quotes get their fingerprints once their duplicates are gone
+	if err := pg.SetFingerprints(ctx, fingerprints); err != nil {
+		logger.Error("failed to set fingerprints", "error", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%d duplicates merged\n", duplicates)
+}
@@ -0,0 +1,113 @@
+// Command user manages users API keys are issued for:
+//
+//	user create -name NAME -role contributor
+//	user list
+//	user role NAME ROLE
+//
+// Readers only read, contributors add quotes and change their own ones,
+// editors change every quote, admins do everything.
+package main
+
+import (
+	"app/internal/config"
+	"app/internal/domain/access"
+	"app/internal/logger"
+	"app/internal/services/users"
+	"app/internal/storage/postgres"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `usage:
+  user create -name NAME [-role ROLE]
+  user list
+  user role NAME ROLE
+
+roles: reader, contributor, editor, admin`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	cfg := config.MustRead()
+
+	logger := logger.New(cfg.Log)
+
+	pg, err := postgres.New(ctx, logger, cfg.DbConnString)
+	if err != nil {
+		logger.Error("failed to create storage", "error", err)
+		os.Exit(1)
+	}
+	defer pg.Close()
+
+	svc := users.New(logger, pg)
+
+	switch os.Args[1] {
+	case "create":
+		err = create(ctx, svc, os.Args[2:])
+	case "list":
+		err = list(ctx, svc)
+	case "role":
+		err = role(ctx, svc, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func create(ctx context.Context, svc *users.Service, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "user name, recorded as the owner of quotes the user adds")
+	role := fs.String("role", string(access.Reader), "role of the user")
+	fs.Parse(args)
+
+	user, err := svc.Create(ctx, *name, *role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %d %q created with role %s\n", user.ID, user.Name, user.Role)
+	return nil
+}
+
+func list(ctx context.Context, svc *users.Service) error {
+	list, err := svc.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tROLE\tCREATED")
+
+	for _, u := range list {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", u.ID, u.Name, u.Role, u.CreatedAt.Format(time.RFC3339))
+	}
+
+	return w.Flush()
+}
+
+func role(ctx context.Context, svc *users.Service, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	if err := svc.SetRole(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+
+	fmt.Printf("user %q is now %s\n", args[0], args[1])
+	return nil
+}
@@ -1,13 +1,38 @@
 package api
 
 import (
+	"app/internal/api/handlers/addtags"
+	"app/internal/api/handlers/author"
+	"app/internal/api/handlers/authorquotes"
+	"app/internal/api/handlers/authors"
+	"app/internal/api/handlers/daily"
 	"app/internal/api/handlers/delete"
+	"app/internal/api/handlers/export"
+	"app/internal/api/handlers/get"
+	"app/internal/api/handlers/importquotes"
 	"app/internal/api/handlers/list"
+	"app/internal/api/handlers/patch"
+	"app/internal/api/handlers/pindaily"
 	"app/internal/api/handlers/random"
+	"app/internal/api/handlers/removetag"
+	"app/internal/api/handlers/restore"
 	"app/internal/api/handlers/save"
+	"app/internal/api/handlers/search"
+	"app/internal/api/handlers/similar"
+	"app/internal/api/handlers/tags"
+	"app/internal/api/handlers/trash"
+	"app/internal/api/handlers/unpindaily"
+	"app/internal/api/handlers/update"
+	"app/internal/api/middleware/auth"
 	"app/internal/api/middleware/json"
 	mwLogger "app/internal/api/middleware/logger"
+	"app/internal/api/middleware/ratelimit"
 	requestid "app/internal/api/middleware/requestID"
+	"app/internal/config"
+	"app/internal/lib/clientip"
+	"app/internal/lib/jwtauth"
+	"app/internal/lib/tokenbucket"
+	"app/internal/services/apikeys"
 	"app/internal/services/quteos"
 	"app/internal/storage"
 	"fmt"
@@ -18,25 +43,71 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// Roles of JWTs issued by the gateway.
+const (
+	RoleReader      = "quotes.reader"
+	RoleContributor = "quotes.contributor"
+	RoleEditor      = "quotes.editor"
+	RoleAdmin       = "quotes.admin"
+)
+
 type API struct {
 	Router  mux.Router
 	Storage storage.Storage
 	Service *quteos.Service
+	Keys    *apikeys.Service
+	Auth    *auth.Auth
+	Limiter *ratelimit.Limiter
 	Log     *slog.Logger
+
+	accessLog mwLogger.Options
 }
 
-func New(storage storage.Storage, log *slog.Logger) *API {
+// New creates the API. jwks is nil when JWTs are not trusted, buckets keeps
+// the token buckets of the rate limiter.
+func New(storage storage.Storage, log *slog.Logger, cfg *config.Config, jwks *jwtauth.KeySet, buckets tokenbucket.Store) *API {
 	router := mux.NewRouter()
 
 	api := &API{
 		Router:  *router,
 		Storage: storage,
 		Log:     log,
+		accessLog: mwLogger.Options{
+			Slow:    cfg.AccessLogSlow,
+			Exclude: cfg.AccessLogExclude,
+			Sample:  cfg.AccessLogSample,
+		},
 	}
 
-	api.Service = quteos.New(&storage, log)
+	api.Service = quteos.New(&storage, log, quteos.Settings{
+		AuthorSimilarity: cfg.AuthorSimilarity,
+		QuoteSimilarity:  cfg.QuoteSimilarity,
+		AllowedScripts:   cfg.AllowedScripts,
+	})
+
+	api.Keys = apikeys.New(log, storage)
 
-	api.Middlewares()
+	var verifier auth.TokenVerifier
+	if jwks != nil {
+		verifier = jwtauth.NewVerifier(jwks, cfg.JWTIssuer, cfg.JWTAudience)
+	}
+
+	// limits and proxies are checked by config.MustRead
+	limits := make(map[string]tokenbucket.Limit)
+	for group, limit := range map[string]string{
+		ratelimit.GroupRead:   cfg.RateLimitRead,
+		ratelimit.GroupWrite:  cfg.RateLimitWrite,
+		ratelimit.GroupDelete: cfg.RateLimitDelete,
+	} {
+		limits[group], _ = tokenbucket.ParseLimit(limit)
+	}
+	if cfg.AuthEnabled {
+		// without authentication nothing fails it, so there is nothing to limit
+		limits[ratelimit.GroupAuth], _ = tokenbucket.ParseLimit(cfg.RateLimitAuth)
+	}
+	trusted, _ := clientip.ParseTrusted(cfg.TrustedProxies)
+	api.Limiter = ratelimit.New(log, buckets, limits, trusted)
+	api.Auth = auth.New(log, api.Keys, verifier, api.Limiter.Failures(ratelimit.GroupAuth), cfg.AuthEnabled)
 
 	api.Endpoints()
 
@@ -47,20 +118,60 @@ func (a *API) Endpoints() {
 
 	v1 := a.Router.PathPrefix("/api/v1").Subrouter()
 
-	v1.Handle("/quotes", json.JSONContentTypeMW(save.New(a.Log, a.Service))).Methods(http.MethodPost)
-	v1.Handle("/quotes", json.JSONContentTypeMW(list.New(a.Log, a.Service))).Methods(http.MethodGet)
-	v1.Handle("/quotes/random", json.JSONContentTypeMW(random.New(a.Log, a.Service))).Methods(http.MethodGet)
-	v1.Handle("/quotos/{id:[0-9]+}", json.JSONContentTypeMW(delete.New(a.Log, a.Service))).Methods(http.MethodDelete)
+	// API keys need the scope, JWTs one of the roles. Rate limits follow
+	// authentication, so clients are limited by key rather than address.
+	read := chain(a.Auth.Require(apikeys.ScopeRead, RoleReader, RoleContributor, RoleEditor, RoleAdmin), a.Limiter.Limit(ratelimit.GroupRead))
+	write := chain(a.Auth.Require(apikeys.ScopeWrite, RoleContributor, RoleEditor, RoleAdmin), a.Limiter.Limit(ratelimit.GroupWrite))
+	del := chain(a.Auth.Require(apikeys.ScopeDelete, RoleContributor, RoleEditor, RoleAdmin), a.Limiter.Limit(ratelimit.GroupDelete))
+	admin := chain(a.Auth.Require(apikeys.ScopeWrite, RoleAdmin), a.Limiter.Limit(ratelimit.GroupWrite))
+	// the trash is read by contributors and up, readers have no deleted quotes
+	readOwn := chain(a.Auth.Require(apikeys.ScopeRead, RoleContributor, RoleEditor, RoleAdmin), a.Limiter.Limit(ratelimit.GroupRead))
+
+	v1.Handle("/quotes", write(json.JSONContentTypeMW(save.New(a.Log, a.Service)))).Methods(http.MethodPost)
+	v1.Handle("/quotes", read(json.JSONContentTypeMW(list.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/quotes/export", read(export.New(a.Log, a.Service))).Methods(http.MethodGet)
+	v1.Handle("/quotes/import", write(json.JSONContentTypeMW(importquotes.New(a.Log, a.Service)))).Methods(http.MethodPost)
+	v1.Handle("/quotes/random", read(json.JSONContentTypeMW(random.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/quotes/daily", read(json.JSONContentTypeMW(daily.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/quotes/daily/{date}", admin(json.JSONContentTypeMW(pindaily.New(a.Log, a.Service)))).Methods(http.MethodPut)
+	v1.Handle("/quotes/daily/{date}", admin(json.JSONContentTypeMW(unpindaily.New(a.Log, a.Service)))).Methods(http.MethodDelete)
+	v1.Handle("/quotes/search", read(json.JSONContentTypeMW(search.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/quotes/{id}", read(json.JSONContentTypeMW(get.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/quotes/{id}", write(json.JSONContentTypeMW(update.New(a.Log, a.Service)))).Methods(http.MethodPut)
+	v1.Handle("/quotes/{id}", write(json.JSONContentTypeMW(patch.New(a.Log, a.Service)))).Methods(http.MethodPatch)
+	v1.Handle("/quotes/{id:[0-9]+}", del(json.JSONContentTypeMW(delete.New(a.Log, a.Service)))).Methods(http.MethodDelete)
+	v1.Handle("/quotes/{id}/restore", write(json.JSONContentTypeMW(restore.New(a.Log, a.Service)))).Methods(http.MethodPost)
+	v1.Handle("/quotes/{id}/similar", read(json.JSONContentTypeMW(similar.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/quotes/{id}/tags", write(json.JSONContentTypeMW(addtags.New(a.Log, a.Service)))).Methods(http.MethodPost)
+	v1.Handle("/quotes/{id}/tags/{tag}", write(json.JSONContentTypeMW(removetag.New(a.Log, a.Service)))).Methods(http.MethodDelete)
+	v1.Handle("/tags", read(json.JSONContentTypeMW(tags.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/authors", read(json.JSONContentTypeMW(authors.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/authors/{id}", read(json.JSONContentTypeMW(author.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/authors/{id}/quotes", read(json.JSONContentTypeMW(authorquotes.New(a.Log, a.Service)))).Methods(http.MethodGet)
+	v1.Handle("/trash", readOwn(json.JSONContentTypeMW(trash.New(a.Log, a.Service)))).Methods(http.MethodGet)
 
 	Routes(a.Log, &a.Router)
 }
 
-func (a *API) Middlewares() {
-	a.Router.Use(
-		requestid.RequestIdMw,
-		mwLogger.New(a.Log),
-	)
+// chain returns a middleware running mws in order, the first one outermost.
+func chain(mws ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
 
+		return next
+	}
+}
+
+// Handler is the router wrapped by the middlewares every request goes
+// through. Router.Use would skip requests without a matching route, so the
+// 404 and 405 responses would get neither a request ID nor an access line.
+func (a *API) Handler() http.Handler {
+	return chain(
+		requestid.RequestIdMw,
+		mwLogger.New(a.Log, a.accessLog),
+	)(&a.Router)
 }
 
 func Routes(log *slog.Logger, router *mux.Router) {
@@ -0,0 +1,49 @@
+package addtags
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/etag"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type Request struct {
+	Tags []string `json:"tags"`
+}
+
+type TagAdder interface {
+	AddTags(ctx context.Context, id string, tags []string) (*storage.StorageQuote, error)
+}
+
+func New(log *slog.Logger, adder TagAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		var req Request
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierr.Write(w, r, log, apierr.ErrInvalidBody)
+			return
+		}
+		defer r.Body.Close()
+
+		quote, err := adder.AddTags(reqCtx, id, req.Tags)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quote tags added", "id", quote.Id, "tags", quote.Tags)
+		etag.Set(w, quote.Version)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(quote))
+	}
+}
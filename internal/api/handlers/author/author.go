@@ -0,0 +1,35 @@
+package author
+
+import (
+	"app/internal/domain/models"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type AuthorGetter interface {
+	GetAuthor(ctx context.Context, id string) (*models.Author, error)
+}
+
+func New(log *slog.Logger, getter AuthorGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		author, err := getter.GetAuthor(reqCtx, id)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "author retrieved successfully", "id", author.ID)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(author))
+	}
+}
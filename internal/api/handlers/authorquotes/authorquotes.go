@@ -0,0 +1,45 @@
+package authorquotes
+
+import (
+	"app/internal/api/handlers/list"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/link"
+	"app/internal/lib/api/response"
+	"app/internal/services/quteos"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type AuthorQuotesGetter interface {
+	ListAuthorQuotes(ctx context.Context, id string, req quteos.PageRequest) (*quteos.QuotesPage, error)
+}
+
+func New(log *slog.Logger, getter AuthorQuotesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		pageReq, err := list.ParsePageRequest(r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		page, err := getter.ListAuthorQuotes(reqCtx, id, pageReq)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "author quotes listed successfully", "id", id, "count", len(page.Quotes))
+
+		link.SetNext(w, r, page.NextCursor)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.Page(page.Quotes, page.NextCursor, page.Total))
+	}
+}
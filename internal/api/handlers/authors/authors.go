@@ -0,0 +1,41 @@
+package authors
+
+import (
+	"app/internal/api/handlers/list"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/link"
+	"app/internal/lib/api/response"
+	"app/internal/services/quteos"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+type AuthorsGetter interface {
+	ListAuthors(ctx context.Context, req quteos.PageRequest) (*quteos.AuthorsPage, error)
+}
+
+func New(log *slog.Logger, getter AuthorsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		pageReq, err := list.ParsePageRequest(r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		page, err := getter.ListAuthors(reqCtx, pageReq)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "authors listed successfully", "count", len(page.Authors))
+
+		link.SetNext(w, r, page.NextCursor)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.Page(page.Authors, page.NextCursor, page.Total))
+	}
+}
@@ -0,0 +1,43 @@
+package daily
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"app/internal/services/quteos"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type DailyGetter interface {
+	DailyQuote(ctx context.Context, tz string) (*quteos.DailyQuote, error)
+}
+
+func New(log *slog.Logger, getter DailyGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		tz := r.URL.Query().Get("tz")
+
+		daily, err := getter.DailyQuote(reqCtx, tz)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		// the quote changes at the next local midnight, caches may keep it until then
+		maxAge := int(time.Until(daily.Expires).Seconds())
+		if maxAge < 0 {
+			maxAge = 0
+		}
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+		w.Header().Set("Expires", daily.Expires.UTC().Format(http.TimeFormat))
+
+		log.InfoContext(reqCtx, "daily quote retrieved successfully", "date", daily.Date, "id", daily.Quote.Id, "pinned", daily.Pinned)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(daily))
+	}
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/quoteio"
+	"app/internal/services/quteos"
+	"app/internal/storage"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// flushEvery is the number of rows written between flushes of the response.
+const flushEvery = 100
+
+type Exporter interface {
+	Export(ctx context.Context, filter quteos.ExportFilter, fn func(q *storage.StorageQuote) error) error
+}
+
+func New(log *slog.Logger, exporter Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		query := r.URL.Query()
+
+		format := query.Get("format")
+		if format == "" {
+			format = quoteio.FormatNDJSON
+		}
+
+		if quoteio.ContentType(format) == "" {
+			apierr.Write(w, r, log, apierr.InvalidArgument(quoteio.ErrUnsupportedFormat.Error()))
+			return
+		}
+
+		filter := quteos.ExportFilter{
+			Author:      query.Get("author"),
+			AuthorMatch: query.Get("match"),
+			Tags:        query["tag"],
+			TagMatch:    query.Get("tag_match"),
+		}
+
+		if filter.Author != "" && len(filter.Tags) > 0 {
+			apierr.Write(w, r, log, apierr.InvalidArgument("Author and tag query parameters cannot be combined"))
+			return
+		}
+
+		// the response starts with the first row, so that filter errors
+		// can still be reported with a status code
+		var out *quoteio.Writer
+
+		start := func() error {
+			filename := "quotes-" + time.Now().UTC().Format(time.DateOnly) + "." + format
+
+			w.Header().Set("Content-Type", quoteio.ContentType(format))
+			w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+			w.WriteHeader(http.StatusOK)
+
+			var err error
+			out, err = quoteio.NewWriter(format, w)
+			return err
+		}
+
+		flusher, _ := w.(http.Flusher)
+		exported := 0
+
+		err := exporter.Export(reqCtx, filter, func(q *storage.StorageQuote) error {
+			if out == nil {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			if err := out.Write(&q.Quote); err != nil {
+				return err
+			}
+
+			exported++
+			if exported%flushEvery == 0 && flusher != nil {
+				if err := out.Flush(); err != nil {
+					return err
+				}
+				flusher.Flush()
+			}
+
+			return nil
+		})
+		if err != nil {
+			if out != nil {
+				// the status is already sent, the client gets a truncated document
+				log.ErrorContext(reqCtx, "export interrupted", "error", err, "exported", exported)
+				return
+			}
+
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		if out == nil {
+			if err := start(); err != nil {
+				log.ErrorContext(reqCtx, "failed to start export", "error", err)
+				return
+			}
+		}
+
+		if err := out.Close(); err != nil {
+			log.ErrorContext(reqCtx, "failed to finish export", "error", err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quotes exported", "format", format, "count", exported)
+	}
+}
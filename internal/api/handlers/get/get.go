@@ -0,0 +1,52 @@
+package get
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/etag"
+	"app/internal/lib/api/render"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type Getter interface {
+	Get(ctx context.Context, id string) (*storage.StorageQuote, error)
+}
+
+func New(log *slog.Logger, getter Getter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		format, err := render.Negotiate(w, r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+
+		quote, err := getter.Get(reqCtx, id)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quote retrieved successfully", "id", quote.Id)
+		etag.Set(w, quote.Version)
+
+		if format != render.JSON {
+			if err := render.Write(w, format, []*storage.StorageQuote{quote}); err != nil {
+				apierr.Write(w, r, log, err)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(quote))
+	}
+}
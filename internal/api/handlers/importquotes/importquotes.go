@@ -0,0 +1,63 @@
+package importquotes
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"app/internal/lib/quoteio"
+	"app/internal/services/quteos"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// maxBodySize limits import documents, MaxImportRows rows of maximal length fit in.
+const maxBodySize = 10 << 20
+
+var (
+	errUnsupportedFormat = errcode.New(errcode.UnsupportedMediaType, "Content-Type must be application/json, application/x-ndjson or text/csv")
+	errTooLarge          = errcode.New(errcode.PayloadTooLarge, "Import document is too large")
+)
+
+type Importer interface {
+	Import(ctx context.Context, rows []quoteio.Row, mode string) (*quteos.ImportReport, error)
+}
+
+func New(log *slog.Logger, importer Importer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		format, err := quoteio.FormatFromContentType(r.Header.Get("Content-Type"))
+		if err != nil {
+			apierr.Write(w, r, log, errUnsupportedFormat)
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, maxBodySize)
+		defer body.Close()
+
+		rows, err := quoteio.Read(format, body, quteos.MaxImportRows)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				apierr.Write(w, r, log, errTooLarge)
+				return
+			}
+
+			apierr.Write(w, r, log, errcode.New(errcode.InvalidBody, err.Error()))
+			return
+		}
+
+		report, err := importer.Import(reqCtx, rows, r.URL.Query().Get("mode"))
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quotes imported", "created", report.Created, "failed", report.Failed)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(report))
+	}
+}
@@ -1,101 +1,106 @@
 package list
 
 import (
-	requestid "app/internal/api/middleware/requestID"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/link"
+	"app/internal/lib/api/render"
 	"app/internal/lib/api/response"
-	"app/internal/storage"
+	"app/internal/services/quteos"
 	"context"
 	"encoding/json"
-	"errors"
 	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
 type ListGetter interface {
-	List(ctx context.Context) ([]*storage.StorageQuote, error)
-	ListByAuthor(ctx context.Context, author string) ([]*storage.StorageQuote, error)
+	List(ctx context.Context, req quteos.PageRequest) (*quteos.QuotesPage, error)
+	ListByAuthor(ctx context.Context, author string, match string, req quteos.PageRequest) (*quteos.QuotesPage, error)
+	ListByTags(ctx context.Context, tags []string, match string, req quteos.PageRequest) (*quteos.QuotesPage, error)
 }
 
 func New(log *slog.Logger, listGetter ListGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		reqCtx := r.Context()
 
-		log := log.With("requestID", reqCtx.Value(requestid.ContextKeyRequestID))
+		format, err := render.Negotiate(w, r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
 
-		var list []*storage.StorageQuote
+		pageReq, err := ParsePageRequest(r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		var page *quteos.QuotesPage
 
 		author := r.URL.Query().Get("author")
+		tags := r.URL.Query()["tag"]
+
+		if author != "" && len(tags) > 0 {
+			apierr.Write(w, r, log, apierr.InvalidArgument("Author and tag query parameters cannot be combined"))
+			return
+		}
 
 		// Search by quert param author
 		if author != "" {
+			page, err = listGetter.ListByAuthor(reqCtx, author, r.URL.Query().Get("match"), pageReq)
+		} else if len(tags) > 0 {
+			page, err = listGetter.ListByTags(reqCtx, tags, r.URL.Query().Get("tag_match"), pageReq)
+		} else {
+			page, err = listGetter.List(reqCtx, pageReq)
+		}
 
-			if len(author) < 3 || len(author) > 100 {
-				log.Error("author query parameter is not valid", "author", author, "code", http.StatusBadRequest)
-
-				w.WriteHeader(http.StatusBadRequest)
-				json.NewEncoder(w).Encode(response.Error("Author query parameter is not valid. It must be between 3 and 100 characters long."))
-				return
-			}
-
-			author = strings.ReplaceAll(author, "_", " ")
-
-			list, err := FindByAuthor(reqCtx, author, listGetter)
-			if err != nil {
-				if errors.Is(err, storage.ErrQuotesListEmpty) {
-					log.Info("quotes list is empty", "error", err)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
 
-					w.WriteHeader(http.StatusOK)
-					json.NewEncoder(w).Encode(response.OKWithPayload([]*storage.StorageQuote{}))
-					return
-				}
+		log.InfoContext(reqCtx, "quotes listed successfully", "author", author, "tags", tags, "count", len(page.Quotes))
 
-				log.Error("failed to list quotes by author", "error", err, "code", http.StatusInternalServerError)
+		link.SetNext(w, r, page.NextCursor)
 
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(response.Error("Internal server error"))
-				return
+		if format != render.JSON {
+			if err := render.Write(w, format, page.Quotes); err != nil {
+				apierr.Write(w, r, log, err)
 			}
-
-			log.Info("quotes listed successfully by author", "author", author, "count", len(list))
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(response.OKWithPayload(list))
 			return
-
 		}
 
-		// If no author query param, list all quotes
-		list, err := SimpleFind(reqCtx, listGetter)
-		if err != nil {
-			if errors.Is(err, storage.ErrQuotesListEmpty) {
-				log.Info("quotes list is empty", "error", err)
-			}
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(response.OKWithPayload(list))
-
-		}
-
-		log.Info("quotes listed successfully", "count", len(list))
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(response.OKWithPayload(list))
-
+		json.NewEncoder(w).Encode(response.Page(page.Quotes, page.NextCursor, page.Total))
 	}
 }
 
-func FindByAuthor(reqCtx context.Context, author string, listGetter ListGetter) ([]*storage.StorageQuote, error) {
-	list, err := listGetter.ListByAuthor(reqCtx, author)
-	if err != nil {
-		return nil, err
+// errInvalidPage rejects malformed limit and total query parameters.
+var errInvalidPage = apierr.InvalidArgument("Query parameters limit and total are not valid")
+
+// ParsePageRequest reads limit, cursor and total query parameters.
+func ParsePageRequest(r *http.Request) (quteos.PageRequest, error) {
+	query := r.URL.Query()
+
+	req := quteos.PageRequest{
+		Cursor: query.Get("cursor"),
 	}
 
-	return list, nil
-}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return req, errInvalidPage
+		}
+		req.Limit = n
+	}
 
-func SimpleFind(reqCtx context.Context, listGetter ListGetter) ([]*storage.StorageQuote, error) {
-	list, err := listGetter.List(reqCtx)
-	if err != nil {
-		return nil, err
+	if total := query.Get("total"); total != "" {
+		withTotal, err := strconv.ParseBool(total)
+		if err != nil {
+			return req, errInvalidPage
+		}
+		req.WithTotal = withTotal
 	}
 
-	return list, nil
+	return req, nil
 }
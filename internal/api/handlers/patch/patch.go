@@ -0,0 +1,90 @@
+package patch
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/domain/models"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/etag"
+	"app/internal/lib/api/response"
+	"app/internal/lib/mergepatch"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+const maxPatchSize = 1 << 16
+
+var errInvalidPatch = errcode.New(errcode.InvalidBody, "Invalid merge patch")
+
+type Patcher interface {
+	Get(ctx context.Context, id string) (*storage.StorageQuote, error)
+	Update(ctx context.Context, id string, q *models.Quote, version int) (*storage.StorageQuote, error)
+}
+
+// New handles PATCH requests with a JSON Merge Patch (RFC 7396) body.
+func New(log *slog.Logger, patcher Patcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		version, err := etag.IfMatch(r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxPatchSize))
+		if err != nil {
+			apierr.Write(w, r, log, apierr.ErrInvalidBody)
+			return
+		}
+		defer r.Body.Close()
+
+		current, err := patcher.Get(reqCtx, id)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		if current.Version != version {
+			apierr.Write(w, r, log, storage.ErrVersionConflict)
+			return
+		}
+
+		doc, err := json.Marshal(current.Quote)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		patched, err := mergepatch.Apply(doc, body)
+		if err != nil {
+			apierr.Write(w, r, log, errInvalidPatch)
+			return
+		}
+
+		var req models.Quote
+		if err := json.Unmarshal(patched, &req); err != nil {
+			apierr.Write(w, r, log, errInvalidPatch)
+			return
+		}
+
+		quote, err := patcher.Update(reqCtx, id, &req, version)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quote patched", "id", quote.Id, "version", quote.Version)
+
+		etag.Set(w, quote.Version)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(quote))
+	}
+}
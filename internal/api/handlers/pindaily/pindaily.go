@@ -0,0 +1,45 @@
+package pindaily
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type Request struct {
+	QuoteID int `json:"quote_id"`
+}
+
+type DailyPinner interface {
+	PinDaily(ctx context.Context, date string, quoteID int) error
+}
+
+func New(log *slog.Logger, pinner DailyPinner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		date := mux.Vars(r)["date"]
+
+		var req Request
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierr.Write(w, r, log, apierr.ErrInvalidBody)
+			return
+		}
+		defer r.Body.Close()
+
+		if err := pinner.PinDaily(reqCtx, date, req.QuoteID); err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "daily quote pinned", "date", date, "id", req.QuoteID)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OK())
+	}
+}
@@ -1,46 +1,65 @@
 package random
 
 import (
-	requestid "app/internal/api/middleware/requestID"
 	"app/internal/domain/models"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/render"
 	"app/internal/lib/api/response"
 	"app/internal/storage"
 	"context"
 	"encoding/json"
-	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 )
 
 type RandomGetter interface {
-	RandomQuote(ctx context.Context) (*models.Quote, error)
+	RandomQuote(ctx context.Context, filter storage.RandomFilter) (*models.Quote, error)
 }
 
 func New(log *slog.Logger, getter RandomGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		reqCtx := r.Context()
 
-		log := log.With("requestID", reqCtx.Value(requestid.ContextKeyRequestID))
-
-		quote, err := getter.RandomQuote(reqCtx)
+		format, err := render.Negotiate(w, r)
 		if err != nil {
-			if errors.Is(err, storage.ErrQuotesListEmpty) {
-				log.Info("quotes list is empty", "error", err)
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		query := r.URL.Query()
 
-				w.WriteHeader(http.StatusOK)
-				json.NewEncoder(w).Encode(response.OKWithPayload(nil))
+		filter := storage.RandomFilter{
+			Author: query.Get("author"),
+			Tag:    query.Get("tag"),
+		}
+
+		if maxLength := query.Get("max_length"); maxLength != "" {
+			n, err := strconv.Atoi(maxLength)
+			if err != nil || n <= 0 {
+				apierr.Write(w, r, log, apierr.InvalidArgument("Query parameter max_length must be a positive integer"))
 				return
 			}
-			log.Error("failed to get random quote", "error", err, "code", http.StatusInternalServerError)
+			filter.MaxLength = n
+		}
 
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(response.Error("Internal server error"))
+		quote, err := getter.RandomQuote(reqCtx, filter)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "random quote retrieved successfully", "quote", quote)
+
+		if format != render.JSON {
+			if err := render.Write(w, format, []*storage.StorageQuote{{Quote: *quote}}); err != nil {
+				apierr.Write(w, r, log, err)
+			}
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(response.OKWithPayload(quote))
-		log.Info("random quote retrieved successfully", "quote", quote)
 
 	}
 }
@@ -0,0 +1,38 @@
+package removetag
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/etag"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type TagRemover interface {
+	RemoveTags(ctx context.Context, id string, tags []string) (*storage.StorageQuote, error)
+}
+
+func New(log *slog.Logger, remover TagRemover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		vars := mux.Vars(r)
+		id := vars["id"]
+
+		quote, err := remover.RemoveTags(reqCtx, id, []string{vars["tag"]})
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quote tag removed", "id", quote.Id, "tag", vars["tag"])
+		etag.Set(w, quote.Version)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(quote))
+	}
+}
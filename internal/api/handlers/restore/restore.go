@@ -0,0 +1,34 @@
+package restore
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type Restorer interface {
+	Restore(ctx context.Context, id string) error
+}
+
+func New(log *slog.Logger, restorer Restorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		err := restorer.Restore(reqCtx, id)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quote restored successfully", "id", id)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(map[string]string{"message": "Quote restored successfully"}))
+	}
+}
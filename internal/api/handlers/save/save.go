@@ -1,63 +1,70 @@
 package save
 
 import (
-	requestid "app/internal/api/middleware/requestID"
 	"app/internal/domain/models"
+	"app/internal/lib/api/apierr"
 	"app/internal/lib/api/response"
+	"app/internal/services/quteos"
+	"app/internal/storage"
 	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
-
-	"github.com/go-playground/validator/v10"
+	"strconv"
 )
 
 type Saver interface {
-	Save(ctx context.Context, q *models.Quote) (int, error)
+	Save(ctx context.Context, q *models.Quote, strict bool) (*quteos.SaveResult, error)
+}
+
+// savedQuote is the payload of a created quote. Similar lists near duplicates
+// the quote was saved despite of.
+type savedQuote struct {
+	ID      int                     `json:"id"`
+	Similar []*storage.SimilarQuote `json:"similar,omitempty"`
 }
 
+// New handles POST requests which create a quote. With ?strict=true quotes
+// similar to stored ones are rejected instead of saved with a warning.
+// The quote is validated by the service only, so every way of saving quotes
+// follows the same rules.
 func New(log *slog.Logger, saver Saver) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		reqCtx := r.Context()
 
-		log := log.With("requestID", reqCtx.Value(requestid.ContextKeyRequestID))
+		strict := false
+		if s := r.URL.Query().Get("strict"); s != "" {
+			var err error
+			strict, err = strconv.ParseBool(s)
+			if err != nil {
+				apierr.Write(w, r, log, apierr.InvalidArgument("Strict query parameter must be true or false"))
+				return
+			}
+		}
 
 		var req models.Quote
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(response.Error("Invalid request body"))
+			apierr.Write(w, r, log, apierr.ErrInvalidBody)
 			return
 		}
 		defer r.Body.Close()
 
-		err := validator.New().Struct(&req)
+		result, err := saver.Save(reqCtx, &req, strict)
 		if err != nil {
-			validatorErr := err.(validator.ValidationErrors)
-
-			log.Error("Invalid request body", "error", validatorErr)
-
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(response.ValidationError(validatorErr))
+			apierr.Write(w, r, log, err)
 			return
 		}
 
-		id, err := saver.Save(reqCtx, &req)
-		if err != nil {
-			log.Error("failed to save quote", "error", err, "code", http.StatusInternalServerError)
-
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(response.Error("Internal server error"))
-
-			return
-
+		if len(result.Similar) > 0 {
+			log.WarnContext(reqCtx, "quote is similar to stored ones", "id", result.ID, "similar", len(result.Similar))
 		}
 
-		log.Info("quote saved", "id", id)
+		log.InfoContext(reqCtx, "quote saved", "id", result.ID)
 
 		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(response.OKWithPayload(map[string]int{"id": id}))
+		json.NewEncoder(w).Encode(response.OKWithPayload(savedQuote{ID: result.ID, Similar: result.Similar}))
 
 	}
 }
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Searcher interface {
+	Search(ctx context.Context, query string, lang string, limit int) ([]*storage.SearchResult, error)
+}
+
+func New(log *slog.Logger, searcher Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		query := r.URL.Query()
+
+		limit := 0
+		if l := query.Get("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil {
+				apierr.Write(w, r, log, apierr.InvalidArgument("Limit query parameter must be a positive integer"))
+				return
+			}
+			limit = n
+		}
+
+		results, err := searcher.Search(reqCtx, query.Get("q"), query.Get("lang"), limit)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quotes searched successfully", "count", len(results))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(results))
+	}
+}
@@ -0,0 +1,47 @@
+package similar
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type SimilarFinder interface {
+	Similar(ctx context.Context, id string, limit int) ([]*storage.SimilarQuote, error)
+}
+
+// New lists the quotes closest to the quote {id} with their scores.
+func New(log *slog.Logger, finder SimilarFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		limit := 0
+		if l := r.URL.Query().Get("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil {
+				apierr.Write(w, r, log, apierr.InvalidArgument("Limit query parameter must be a positive integer"))
+				return
+			}
+			limit = n
+		}
+
+		similar, err := finder.Similar(reqCtx, id, limit)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "similar quotes found", "id", id, "count", len(similar))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(similar))
+	}
+}
@@ -0,0 +1,31 @@
+package tags
+
+import (
+	"app/internal/domain/models"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+type TagsGetter interface {
+	Tags(ctx context.Context) ([]*models.Tag, error)
+}
+
+func New(log *slog.Logger, getter TagsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		tags, err := getter.Tags(reqCtx)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "tags listed successfully", "count", len(tags))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(tags))
+	}
+}
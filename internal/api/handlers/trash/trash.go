@@ -0,0 +1,31 @@
+package trash
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+type TrashGetter interface {
+	Trash(ctx context.Context) ([]*storage.StorageQuote, error)
+}
+
+func New(log *slog.Logger, getter TrashGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		list, err := getter.Trash(reqCtx)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "deleted quotes listed successfully", "count", len(list))
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(list))
+	}
+}
@@ -0,0 +1,33 @@
+package unpindaily
+
+import (
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/response"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type DailyUnpinner interface {
+	UnpinDaily(ctx context.Context, date string) error
+}
+
+func New(log *slog.Logger, unpinner DailyUnpinner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		date := mux.Vars(r)["date"]
+
+		if err := unpinner.UnpinDaily(reqCtx, date); err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "daily quote unpinned", "date", date)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OK())
+	}
+}
@@ -0,0 +1,54 @@
+package update
+
+import (
+	"app/internal/domain/models"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/api/etag"
+	"app/internal/lib/api/response"
+	"app/internal/storage"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type Updater interface {
+	Update(ctx context.Context, id string, q *models.Quote, version int) (*storage.StorageQuote, error)
+}
+
+// New handles PUT requests which fully replace a quote.
+func New(log *slog.Logger, updater Updater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := r.Context()
+
+		id := mux.Vars(r)["id"]
+
+		version, err := etag.IfMatch(r)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		var req models.Quote
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierr.Write(w, r, log, apierr.ErrInvalidBody)
+			return
+		}
+		defer r.Body.Close()
+
+		quote, err := updater.Update(reqCtx, id, &req, version)
+		if err != nil {
+			apierr.Write(w, r, log, err)
+			return
+		}
+
+		log.InfoContext(reqCtx, "quote updated", "id", quote.Id, "version", quote.Version)
+
+		etag.Set(w, quote.Version)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response.OKWithPayload(quote))
+	}
+}
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"app/internal/domain/access"
+	"app/internal/domain/errcode"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/jwtauth"
+	"app/internal/services/apikeys"
+	"app/internal/storage"
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type contextKey string
+
+const (
+	ContextKeyAPIKey  contextKey = "apiKey"
+	ContextKeySubject contextKey = "subject" // sub claim of a verified JWT
+	ContextKeyRoles   contextKey = "roles"   // roles claim of a verified JWT
+)
+
+// rolePrefix namespaces the roles of this service in the roles claim of JWTs.
+const rolePrefix = "quotes."
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*storage.APIKey, *access.Principal, error)
+}
+
+type TokenVerifier interface {
+	Verify(token string) (*jwtauth.Claims, error)
+}
+
+// FailureLimiter limits failed authentications. Fail is called for every
+// request with invalid credentials, once the caller has failed too often it
+// answers the request itself and returns true.
+type FailureLimiter interface {
+	Fail(w http.ResponseWriter, r *http.Request) bool
+}
+
+// Auth checks API keys sent as Authorization: Bearer <key> or X-API-Key and,
+// when a verifier is set, JWTs sent as Authorization: Bearer <token>.
+// A disabled Auth lets every request through.
+type Auth struct {
+	log      *slog.Logger
+	authn    Authenticator
+	verifier TokenVerifier  // nil when JWTs are not trusted
+	failures FailureLimiter // nil when failures are not limited
+	enabled  bool
+}
+
+func New(log *slog.Logger, authn Authenticator, verifier TokenVerifier, failures FailureLimiter, enabled bool) *Auth {
+	if !enabled {
+		log.Warn("API key authentication is disabled")
+	}
+
+	return &Auth{
+		log:      log,
+		authn:    authn,
+		verifier: verifier,
+		failures: failures,
+		enabled:  enabled,
+	}
+}
+
+// Require returns a middleware that answers 401 without valid credentials,
+// or whatever the FailureLimiter answers, and 403 when an API key lacks the
+// scope or a JWT has none of the roles.
+// The key, or the subject and roles of the JWT, are put into the request
+// context together with the access.Principal services check.
+func (a *Auth) Require(scope string, roles ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !a.enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := r.Context()
+
+			credential := token(r)
+
+			if a.verifier != nil && jwtauth.IsJWT(credential) {
+				claims, err := a.verifier.Verify(credential)
+				if err != nil {
+					a.unauthorized(w, r, `Bearer realm="quotes", error="invalid_token"`, err)
+					return
+				}
+
+				if !slices.ContainsFunc(roles, func(role string) bool { return slices.Contains(claims.Roles, role) }) {
+					a.log.InfoContext(reqCtx, "token lacks roles", "subject", claims.Subject, "roles", claims.Roles, "required", roles)
+					apierr.Write(w, r, a.log, jwtauth.ErrNoRole)
+					return
+				}
+
+				reqCtx = context.WithValue(reqCtx, ContextKeySubject, claims.Subject)
+				reqCtx = context.WithValue(reqCtx, ContextKeyRoles, claims.Roles)
+				reqCtx = access.WithPrincipal(reqCtx, principal(claims))
+				next.ServeHTTP(w, r.WithContext(reqCtx))
+				return
+			}
+
+			key, caller, err := a.authn.Authenticate(reqCtx, credential)
+			if err != nil {
+				a.unauthorized(w, r, `Bearer realm="quotes"`, err)
+				return
+			}
+
+			if !apikeys.Allows(key, scope) {
+				a.log.InfoContext(reqCtx, "API key lacks scope", "keyID", key.ID, "scope", scope)
+				apierr.Write(w, r, a.log, apikeys.ErrNoScope)
+				return
+			}
+
+			reqCtx = context.WithValue(reqCtx, ContextKeyAPIKey, key)
+			reqCtx = access.WithPrincipal(reqCtx, caller)
+			next.ServeHTTP(w, r.WithContext(reqCtx))
+		})
+	}
+}
+
+// unauthorized answers a request whose credentials were rejected with err.
+// Only rejected credentials count as failures, errors of the key storage
+// do not.
+func (a *Auth) unauthorized(w http.ResponseWriter, r *http.Request, challenge string, err error) {
+	if a.failures != nil && errcode.Of(err).Code == errcode.Unauthenticated && a.failures.Fail(w, r) {
+		return
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	apierr.Write(w, r, a.log, err)
+}
+
+// principal returns the caller of a JWT with the most privileged of its
+// roles, a token without any role of this service acts as a reader.
+func principal(claims *jwtauth.Claims) *access.Principal {
+	p := &access.Principal{Subject: claims.Subject, Role: access.Reader}
+
+	for _, claim := range claims.Roles {
+		name, ok := strings.CutPrefix(claim, rolePrefix)
+		if !ok {
+			continue
+		}
+		if role, ok := access.ParseRole(name); ok && role.AtLeast(p.Role) {
+			p.Role = role
+		}
+	}
+
+	return p
+}
+
+// token reads the credential from the Authorization header, falling back to X-API-Key.
+func token(r *http.Request) string {
+	if scheme, credentials, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(credentials)
+	}
+
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
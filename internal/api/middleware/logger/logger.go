@@ -1,43 +1,107 @@
 package logger
 
 import (
-	requestid "app/internal/api/middleware/requestID"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"time"
 )
 
-func New(log *slog.Logger) func(next http.Handler) http.Handler {
+// Options tune the access log.
+type Options struct {
+	// Slow requests are logged as warnings, zero disables the check.
+	Slow time.Duration
+	// Exclude are paths never logged, such as health checks.
+	Exclude []string
+	// Sample is the share of successful requests logged, from 0 to 1.
+	// Failed and slow requests are always logged.
+	Sample float64
+}
+
+// New logs one line per request once it is served, with the status, the
+// size of the body and the latency. The level follows the status class:
+// 5xx are errors, 4xx and slow requests warnings, the rest info. The
+// request ID is added by the context of the request, see requestid.RequestIdMw.
+func New(log *slog.Logger, opts Options) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 
 		fn := func(w http.ResponseWriter, r *http.Request) {
 
-			reqId := r.Context().Value(requestid.ContextKeyRequestID)
-			if reqId == nil {
-				log.Error("requestID not found in context")
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			if slices.Contains(opts.Exclude, r.URL.Path) {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			requestID, ok := reqId.(string)
-			if !ok {
-				log.Error("requestID is not a string")
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			start := time.Now()
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			latency := time.Since(start)
+			slow := opts.Slow > 0 && latency >= opts.Slow
+
+			level := slog.LevelInfo
+			switch {
+			case rec.status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case rec.status >= http.StatusBadRequest || slow:
+				level = slog.LevelWarn
+			case opts.Sample < 1 && rand.Float64() >= opts.Sample:
 				return
 			}
 
-			entry := log.With(
+			log.LogAttrs(r.Context(), level, "request completed",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Int64("bytes", rec.bytes),
+				slog.Duration("latency", latency),
+				slog.Bool("slow", slow),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
-				slog.String("request_id", requestID),
 			)
-
-			entry.Info("request received")
-
-			next.ServeHTTP(w, r)
 		}
 		return http.HandlerFunc(fn)
 
 	}
 }
+
+// recorder remembers the status and the size of the response.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+
+	return n, err
+}
+
+// Flush keeps streaming handlers such as the export working.
+func (rec *recorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		rec.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the original writer.
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
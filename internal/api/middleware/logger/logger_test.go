@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// entries returns the access lines logged while serving a request to path.
+func entries(t *testing.T, opts Options, path string, h http.HandlerFunc) []map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	New(log, opts)(h).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+
+	var lines []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var line map[string]any
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("failed to decode log line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func status(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("default status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+		rec.Write([]byte("hello"))
+		rec.WriteHeader(http.StatusTeapot)
+
+		if rec.status != http.StatusOK {
+			t.Errorf("status = %d, expected %d", rec.status, http.StatusOK)
+		}
+		if rec.bytes != 5 {
+			t.Errorf("bytes = %d, expected 5", rec.bytes)
+		}
+	})
+
+	t.Run("first WriteHeader wins", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+		rec.WriteHeader(http.StatusNotFound)
+		rec.WriteHeader(http.StatusInternalServerError)
+		rec.Write([]byte("not found"))
+		rec.Write([]byte("!"))
+
+		if rec.status != http.StatusNotFound {
+			t.Errorf("status = %d, expected %d", rec.status, http.StatusNotFound)
+		}
+		if rec.bytes != 10 {
+			t.Errorf("bytes = %d, expected 10", rec.bytes)
+		}
+		if w.Code != http.StatusNotFound {
+			t.Errorf("response status = %d, expected %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+		if err := http.NewResponseController(rec).Flush(); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+		if !w.Flushed {
+			t.Error("Flush() did not reach the response writer")
+		}
+	})
+}
+
+func TestNew_Level(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{status: http.StatusOK, level: "INFO"},
+		{status: http.StatusFound, level: "INFO"},
+		{status: http.StatusNotFound, level: "WARN"},
+		{status: http.StatusTooManyRequests, level: "WARN"},
+		{status: http.StatusInternalServerError, level: "ERROR"},
+		{status: http.StatusServiceUnavailable, level: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		lines := entries(t, Options{Sample: 1}, "/api/v1/quotes", status(tt.status))
+		if len(lines) != 1 {
+			t.Fatalf("status %d: logged %d lines, expected 1", tt.status, len(lines))
+		}
+		if lines[0]["level"] != tt.level {
+			t.Errorf("status %d: level = %v, expected %s", tt.status, lines[0]["level"], tt.level)
+		}
+		if lines[0]["status"] != float64(tt.status) {
+			t.Errorf("status %d: logged status = %v", tt.status, lines[0]["status"])
+		}
+	}
+}
+
+func TestNew_Slow(t *testing.T) {
+	sleep := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name  string
+		slow  time.Duration
+		level string
+	}{
+		{name: "over threshold", slow: 10 * time.Millisecond, level: "WARN"},
+		{name: "under threshold", slow: time.Minute, level: "INFO"},
+		{name: "disabled", slow: 0, level: "INFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := entries(t, Options{Slow: tt.slow, Sample: 1}, "/", sleep)
+			if len(lines) != 1 {
+				t.Fatalf("logged %d lines, expected 1", len(lines))
+			}
+			if lines[0]["level"] != tt.level {
+				t.Errorf("level = %v, expected %s", lines[0]["level"], tt.level)
+			}
+			if slow := lines[0]["slow"] == true; slow != (tt.level == "WARN") {
+				t.Errorf("slow = %v, expected %v", slow, !slow)
+			}
+		})
+	}
+}
+
+func TestNew_Exclude(t *testing.T) {
+	opts := Options{Exclude: []string{"/health"}, Sample: 1}
+
+	if lines := entries(t, opts, "/health", status(http.StatusInternalServerError)); len(lines) != 0 {
+		t.Errorf("excluded path logged %d lines, expected none", len(lines))
+	}
+	if lines := entries(t, opts, "/health/deep", status(http.StatusOK)); len(lines) != 1 {
+		t.Errorf("other path logged %d lines, expected 1", len(lines))
+	}
+}
+
+func TestNew_Sample(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample float64
+		status int
+		logged int
+	}{
+		{name: "all", sample: 1, status: http.StatusOK, logged: 1},
+		{name: "none", sample: 0, status: http.StatusOK, logged: 0},
+		{name: "client error always", sample: 0, status: http.StatusBadRequest, logged: 1},
+		{name: "server error always", sample: 0, status: http.StatusBadGateway, logged: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if lines := entries(t, Options{Sample: tt.sample}, "/", status(tt.status)); len(lines) != tt.logged {
+				t.Errorf("logged %d lines, expected %d", len(lines), tt.logged)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"app/internal/api/middleware/auth"
+	"app/internal/domain/errcode"
+	"app/internal/lib/api/apierr"
+	"app/internal/lib/clientip"
+	"app/internal/lib/tokenbucket"
+	"app/internal/storage"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+// Route groups limited separately.
+const (
+	GroupRead   = "read"
+	GroupWrite  = "write"
+	GroupDelete = "delete"
+	// GroupAuth limits failed authentications by address.
+	GroupAuth = "auth"
+)
+
+var ErrRateLimited = errcode.New(errcode.RateLimited, "Too many requests, retry later")
+
+// Limiter gives every client a token bucket per route group. Clients are
+// told apart by API key, by the subject of a JWT or by address.
+type Limiter struct {
+	log     *slog.Logger
+	store   tokenbucket.Store
+	limits  map[string]tokenbucket.Limit // by group, groups without one are not limited
+	trusted []netip.Prefix               // proxies whose X-Forwarded-For is believed
+}
+
+func New(log *slog.Logger, store tokenbucket.Store, limits map[string]tokenbucket.Limit, trusted []netip.Prefix) *Limiter {
+	return &Limiter{
+		log:     log,
+		store:   store,
+		limits:  limits,
+		trusted: trusted,
+	}
+}
+
+// Limit returns a middleware allowing each client the limit of the group.
+// Responses carry RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset, rejected ones are 429 with Retry-After. Authentication
+// must run first, so the client is known. Requests are let through when
+// the store fails, an outage of the limiter should not take the API down.
+func (l *Limiter) Limit(group string) func(next http.Handler) http.Handler {
+	limit := l.limits[group]
+
+	return func(next http.Handler) http.Handler {
+		if !limit.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := l.client(r)
+
+			res, err := l.store.TakeToken(r.Context(), group+":"+client, limit)
+			if err != nil {
+				l.log.ErrorContext(r.Context(), "rate limit is not checked", "error", err, "client", client)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", seconds(res.Reset))
+
+			if !res.Allowed {
+				h.Set("Retry-After", seconds(res.RetryAfter))
+				l.log.InfoContext(r.Context(), "rate limited", "client", client, "group", group, "limit", limit)
+				apierr.Write(w, r, l.log, ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Failures returns the auth.FailureLimiter of the group, or nil when the
+// group has no limit. Each failed authentication takes a token from the
+// bucket of its address and is answered with 429 once the bucket is empty.
+// Valid credentials never touch the bucket, so clients sharing an address
+// with someone guessing keys are not locked out.
+func (l *Limiter) Failures(group string) auth.FailureLimiter {
+	limit := l.limits[group]
+	if !limit.Enabled() {
+		return nil
+	}
+
+	return &failures{Limiter: l, group: group, limit: limit}
+}
+
+type failures struct {
+	*Limiter
+	group string
+	limit tokenbucket.Limit
+}
+
+func (f *failures) Fail(w http.ResponseWriter, r *http.Request) bool {
+	ip := clientip.FromRequest(r, f.trusted)
+
+	res, err := f.store.TakeToken(r.Context(), f.group+":ip:"+ip, f.limit)
+	if err != nil {
+		f.log.ErrorContext(r.Context(), "failed authentication is not counted", "error", err, "client", ip)
+		return false
+	}
+	if res.Allowed {
+		return false
+	}
+
+	w.Header().Set("Retry-After", seconds(res.RetryAfter))
+	f.log.InfoContext(r.Context(), "rate limited", "client", ip, "group", f.group, "limit", f.limit)
+	apierr.Write(w, r, f.log, ErrRateLimited)
+
+	return true
+}
+
+func (l *Limiter) client(r *http.Request) string {
+	ctx := r.Context()
+
+	if key, ok := ctx.Value(auth.ContextKeyAPIKey).(*storage.APIKey); ok {
+		return "key:" + strconv.Itoa(key.ID)
+	}
+	if subject, ok := ctx.Value(auth.ContextKeySubject).(string); ok && subject != "" {
+		return "sub:" + subject
+	}
+
+	return "ip:" + clientip.FromRequest(r, l.trusted)
+}
+
+// seconds formats d as whole seconds, rounded up so clients do not retry early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
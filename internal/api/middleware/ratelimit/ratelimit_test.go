@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"app/internal/api/middleware/auth"
+	"app/internal/domain/access"
+	"app/internal/lib/tokenbucket"
+	"app/internal/services/apikeys"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var errStore = errors.New("store is down")
+
+// failingStore is a store that is down.
+type failingStore struct{}
+
+func (failingStore) TakeToken(ctx context.Context, key string, limit tokenbucket.Limit) (tokenbucket.Result, error) {
+	return tokenbucket.Result{}, errStore
+}
+
+// keys accepts the key "valid" with the read scope.
+type keys struct{}
+
+func (keys) Authenticate(ctx context.Context, token string) (*storage.APIKey, *access.Principal, error) {
+	if token != "valid" {
+		return nil, nil, apikeys.ErrInvalidKey
+	}
+
+	return &storage.APIKey{ID: 1, Scopes: []string{apikeys.ScopeRead}}, &access.Principal{Subject: "alice", Role: access.Reader}, nil
+}
+
+func newLimiter(store tokenbucket.Store, limit tokenbucket.Limit) *Limiter {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, store, map[string]tokenbucket.Limit{GroupRead: limit, GroupAuth: limit}, nil)
+}
+
+// serve sends a request from addr through h and returns the response.
+func serve(ctx context.Context, h http.Handler, addr string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/quotes", nil).WithContext(ctx)
+	r.RemoteAddr = addr + ":1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func ok(status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	})
+}
+
+func TestLimiter_Limit(t *testing.T) {
+	ctx := context.Background()
+	h := newLimiter(tokenbucket.NewMemoryStore(), tokenbucket.Limit{Requests: 2, Per: time.Minute}).Limit(GroupRead)(ok(http.StatusOK))
+
+	for remaining := 1; remaining >= 0; remaining-- {
+		w := serve(ctx, h, "192.0.2.1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected %d", w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("RateLimit-Limit = %q, expected 2", got)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(remaining) {
+			t.Errorf("RateLimit-Remaining = %q, expected %d", got, remaining)
+		}
+		if reset, err := strconv.Atoi(w.Header().Get("RateLimit-Reset")); err != nil || reset <= 0 || reset > 60 {
+			t.Errorf("RateLimit-Reset = %q, expected 1 to 60 seconds", w.Header().Get("RateLimit-Reset"))
+		}
+		if got := w.Header().Get("Retry-After"); got != "" {
+			t.Errorf("Retry-After = %q on an allowed request", got)
+		}
+	}
+
+	w := serve(ctx, h, "192.0.2.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, expected %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, expected 30", got)
+	}
+	if got := w.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, expected 0", got)
+	}
+
+	// other clients have their own buckets
+	if w := serve(ctx, h, "192.0.2.2"); w.Code != http.StatusOK {
+		t.Errorf("status of another address = %d, expected %d", w.Code, http.StatusOK)
+	}
+	keyCtx := context.WithValue(ctx, auth.ContextKeyAPIKey, &storage.APIKey{ID: 1})
+	if w := serve(keyCtx, h, "192.0.2.1"); w.Code != http.StatusOK {
+		t.Errorf("status of an API key = %d, expected %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestLimiter_Limit_StoreFailure(t *testing.T) {
+	h := newLimiter(failingStore{}, tokenbucket.Limit{Requests: 1, Per: time.Minute}).Limit(GroupRead)(ok(http.StatusOK))
+
+	for range 3 {
+		w := serve(context.Background(), h, "192.0.2.1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected the request let through", w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "" {
+			t.Errorf("RateLimit-Limit = %q, expected no headers", got)
+		}
+	}
+}
+
+func TestLimiter_Limit_Off(t *testing.T) {
+	h := newLimiter(failingStore{}, tokenbucket.Limit{}).Limit(GroupRead)(ok(http.StatusOK))
+
+	if w := serve(context.Background(), h, "192.0.2.1"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("status = %d, headers = %v, expected an unlimited request", w.Code, w.Header())
+	}
+}
+
+// authenticated returns h behind authentication with failures limited by l.
+func authenticated(l *Limiter, h http.Handler) http.Handler {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return auth.New(log, keys{}, nil, l.Failures(GroupAuth), true).Require(apikeys.ScopeRead)(h)
+}
+
+// serveKey sends a request with key from addr through h and returns the response.
+func serveKey(h http.Handler, addr, key string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/quotes", nil)
+	r.RemoteAddr = addr + ":1234"
+	r.Header.Set("X-API-Key", key)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestLimiter_Failures(t *testing.T) {
+	h := authenticated(newLimiter(tokenbucket.NewMemoryStore(), tokenbucket.Limit{Requests: 2, Per: time.Minute}), ok(http.StatusOK))
+
+	// valid keys take no tokens
+	for range 5 {
+		if w := serveKey(h, "192.0.2.1", "valid"); w.Code != http.StatusOK {
+			t.Fatalf("status = %d, expected %d", w.Code, http.StatusOK)
+		}
+	}
+
+	for range 2 {
+		if w := serveKey(h, "192.0.2.1", "guess"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, expected %d", w.Code, http.StatusUnauthorized)
+		}
+	}
+
+	w := serveKey(h, "192.0.2.1", "guess")
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status after failures = %d, expected %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, expected 30", got)
+	}
+
+	// a valid key behind the same address is not locked out
+	if w := serveKey(h, "192.0.2.1", "valid"); w.Code != http.StatusOK {
+		t.Errorf("status of a valid key after failures = %d, expected %d", w.Code, http.StatusOK)
+	}
+	if w := serveKey(h, "192.0.2.2", "guess"); w.Code != http.StatusUnauthorized {
+		t.Errorf("status of another address = %d, expected %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLimiter_Failures_StoreFailure(t *testing.T) {
+	h := authenticated(newLimiter(failingStore{}, tokenbucket.Limit{Requests: 1, Per: time.Minute}), ok(http.StatusOK))
+
+	for range 3 {
+		if w := serveKey(h, "192.0.2.1", "guess"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, expected the failure answered with %d", w.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestLimiter_Failures_Off(t *testing.T) {
+	if f := newLimiter(failingStore{}, tokenbucket.Limit{}).Failures(GroupAuth); f != nil {
+		t.Errorf("Failures() = %v, expected nil without a limit", f)
+	}
+}
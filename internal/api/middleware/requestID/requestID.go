@@ -1,8 +1,11 @@
 package requestid
 
 import (
+	"app/internal/logger"
 	"context"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -11,14 +14,33 @@ type contextKey string
 
 const ContextKeyRequestID contextKey = "requestID"
 
+const (
+	Header            = "X-Request-ID"
+	TraceparentHeader = "traceparent"
+
+	maxLength = 128
+)
+
+// RequestIdMw takes the request ID from X-Request-ID or the trace ID of a W3C
+// traceparent, generating one when neither is valid. The ID is echoed in
+// X-Request-ID, stored in the context and added to every record logged with it.
 func RequestIdMw(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		ctx := r.Context()
 
-		id := uuid.New()
+		id, ok := valid(r.Header.Get(Header))
+		if !ok {
+			id, ok = traceID(r.Header.Get(TraceparentHeader))
+		}
+		if !ok {
+			id = uuid.New().String()
+		}
 
-		ctx = context.WithValue(ctx, ContextKeyRequestID, id.String())
+		w.Header().Set(Header, id)
+
+		ctx = context.WithValue(ctx, ContextKeyRequestID, id)
+		ctx = logger.WithAttrs(ctx, slog.String("request_id", id))
 
 		r = r.WithContext(ctx)
 
@@ -26,3 +48,68 @@ func RequestIdMw(next http.Handler) http.Handler {
 
 	})
 }
+
+// FromContext returns the request ID, empty outside of requests.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ContextKeyRequestID).(string)
+	return id
+}
+
+// valid accepts IDs of letters, digits and - _ . : up to maxLength long, so
+// they are safe to echo and to log.
+func valid(id string) (string, bool) {
+	id = strings.TrimSpace(id)
+	if id == "" || len(id) > maxLength {
+		return "", false
+	}
+
+	for _, c := range id {
+		if !isAlnum(c) && !strings.ContainsRune("-_.:", c) {
+			return "", false
+		}
+	}
+
+	return id, true
+}
+
+// traceID returns the trace ID of a traceparent header,
+// version-traceid-parentid-flags in lowercase hex.
+func traceID(traceparent string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return "", false
+	}
+
+	version, trace, parent, flags := parts[0], parts[1], parts[2], parts[3]
+
+	// version 00 has exactly four fields, later versions may add more
+	if !isHex(version, 2) || version == "ff" || version == "00" && len(parts) != 4 {
+		return "", false
+	}
+	if !isHex(trace, 32) || !isHex(parent, 16) || !isHex(flags, 2) {
+		return "", false
+	}
+	if strings.Trim(trace, "0") == "" || strings.Trim(parent, "0") == "" {
+		return "", false
+	}
+
+	return trace, true
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlnum(c rune) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
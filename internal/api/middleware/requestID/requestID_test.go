@@ -0,0 +1,108 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id string
+		ok bool
+	}{
+		{id: "abc-123", ok: true},
+		{id: " req_1.2:3 ", ok: true},
+		{id: strings.Repeat("a", maxLength), ok: true},
+		{id: strings.Repeat("a", maxLength+1)},
+		{id: ""},
+		{id: "bad id"},
+		{id: "<script>"},
+		{id: "line\nbreak"},
+		{id: "запрос"},
+	}
+
+	for _, tt := range tests {
+		if _, ok := valid(tt.id); ok != tt.ok {
+			t.Errorf("valid(%q) = %v, expected %v", tt.id, ok, tt.ok)
+		}
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	const (
+		trace  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parent = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		traceparent string
+		ok          bool
+	}{
+		{name: "version 00", traceparent: "00-" + trace + "-" + parent + "-01", ok: true},
+		{name: "later version with more fields", traceparent: "01-" + trace + "-" + parent + "-01-extra", ok: true},
+		{name: "version 00 with more fields", traceparent: "00-" + trace + "-" + parent + "-01-extra"},
+		{name: "version ff", traceparent: "ff-" + trace + "-" + parent + "-01"},
+		{name: "too few fields", traceparent: "00-" + trace + "-" + parent},
+		{name: "zero trace id", traceparent: "00-" + strings.Repeat("0", 32) + "-" + parent + "-01"},
+		{name: "zero parent id", traceparent: "00-" + trace + "-" + strings.Repeat("0", 16) + "-01"},
+		{name: "uppercase hex", traceparent: "00-" + strings.ToUpper(trace) + "-" + parent + "-01"},
+		{name: "short trace id", traceparent: "00-" + trace[:31] + "-" + parent + "-01"},
+		{name: "empty", traceparent: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := traceID(tt.traceparent)
+			if ok != tt.ok {
+				t.Fatalf("traceID(%q) = %q, %v, expected %v", tt.traceparent, id, ok, tt.ok)
+			}
+			if ok && id != trace {
+				t.Errorf("traceID(%q) = %q, expected %q", tt.traceparent, id, trace)
+			}
+		})
+	}
+}
+
+func TestRequestIdMw(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string // empty for a generated ID
+	}{
+		{name: "request id", headers: map[string]string{Header: "abc-123"}, expected: "abc-123"},
+		{name: "request id wins", headers: map[string]string{Header: "abc-123", TraceparentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, expected: "abc-123"},
+		{name: "traceparent", headers: map[string]string{TraceparentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, expected: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "unsafe id", headers: map[string]string{Header: "bad id<script>"}},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromCtx string
+			h := RequestIdMw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromCtx = FromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			echoed := w.Header().Get(Header)
+			if echoed == "" || echoed != fromCtx {
+				t.Fatalf("RequestIdMw() echoed %q and stored %q, expected the same ID", echoed, fromCtx)
+			}
+			if tt.expected != "" && echoed != tt.expected {
+				t.Errorf("RequestIdMw() ID = %q, expected %q", echoed, tt.expected)
+			}
+			if tt.expected == "" && echoed == tt.headers[Header] {
+				t.Errorf("RequestIdMw() kept the invalid ID %q", echoed)
+			}
+		})
+	}
+}
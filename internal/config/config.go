@@ -1,17 +1,68 @@
 package config
 
 import (
+	"app/internal/lib/clientip"
+	"app/internal/lib/tokenbucket"
+	"app/internal/lib/unitext"
 	"log"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
 type Config struct {
-	Log          string `env:"LOG_MODE"`
-	ServerHost   string `env:"SRV_HOST"`
-	ServerPort   string `env:"SRV_PORT" env-default:"8080"`
-	DbConnString string `env:"DB_CONN_STRING, required"`
+	Log           string `env:"LOG_MODE"`
+	ServerHost    string `env:"SRV_HOST"`
+	ServerPort    string `env:"SRV_PORT" env-default:"8080"`
+	StorageDriver string `env:"STORAGE_DRIVER" env-default:"postgres"`
+	// DbConnString is required by the postgres driver only.
+	DbConnString string `env:"DB_CONN_STRING"`
+
+	AuthorSimilarity float64 `env:"AUTHOR_SIMILARITY_THRESHOLD" env-default:"0.3"`
+	QuoteSimilarity  float64 `env:"QUOTE_SIMILARITY_THRESHOLD" env-default:"0.6"`
+
+	// AllowedScripts are Unicode script names (Latin, Cyrillic, ...) letters
+	// of quotes and authors may come from. Empty allows every script.
+	AllowedScripts []string `env:"ALLOWED_SCRIPTS" env-separator:","`
+
+	// AuthEnabled requires API keys with the scopes of each route, see cmd/apikey.
+	AuthEnabled bool `env:"AUTH_ENABLED" env-default:"true"`
+
+	// JWKSFile enables JWTs issued by the gateway, signed with keys of the
+	// file. The file is checked for changes every JWKSReload.
+	JWKSFile    string        `env:"JWT_JWKS_FILE"`
+	JWKSReload  time.Duration `env:"JWT_JWKS_RELOAD" env-default:"1m"`
+	JWTIssuer   string        `env:"JWT_ISSUER"`
+	JWTAudience string        `env:"JWT_AUDIENCE"`
+
+	// RateLimitStore keeps the token buckets of clients: memory limits every
+	// replica on its own, postgres shares the buckets between replicas.
+	RateLimitStore string `env:"RATE_LIMIT_STORE" env-default:"memory"`
+	// Requests a client may send per route group, REQUESTS/DURATION or off.
+	RateLimitRead   string `env:"RATE_LIMIT_READ" env-default:"300/1m"`
+	RateLimitWrite  string `env:"RATE_LIMIT_WRITE" env-default:"60/1m"`
+	RateLimitDelete string `env:"RATE_LIMIT_DELETE" env-default:"60/1m"`
+	// RateLimitAuth is how many failed authentications an address may have.
+	RateLimitAuth string `env:"RATE_LIMIT_AUTH" env-default:"10/1m"`
+	// TrustedProxies are addresses or CIDR ranges of proxies whose
+	// X-Forwarded-For header names the client.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" env-separator:","`
+
+	// Access log: requests slower than AccessLogSlow are warnings, paths of
+	// AccessLogExclude are not logged and only the AccessLogSample share of
+	// successful requests is.
+	AccessLogSlow    time.Duration `env:"ACCESS_LOG_SLOW" env-default:"1s"`
+	AccessLogExclude []string      `env:"ACCESS_LOG_EXCLUDE" env-separator:","`
+	AccessLogSample  float64       `env:"ACCESS_LOG_SAMPLE" env-default:"1"`
+
+	TrashRetention time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+	PurgeInterval  time.Duration `env:"PURGE_INTERVAL" env-default:"1h"`
 }
 
 func MustRead() *Config {
@@ -27,5 +78,55 @@ func MustRead() *Config {
 		log.Fatal(err)
 	}
 
+	if _, err := unitext.Scripts(cfg.AllowedScripts); err != nil {
+		log.Fatal(err)
+	}
+
+	switch cfg.StorageDriver {
+	case DriverMemory:
+		// API keys are created in Postgres, so only JWTs could pass
+		if cfg.AuthEnabled && cfg.JWKSFile == "" {
+			log.Fatal("the memory storage driver has no API keys, set AUTH_ENABLED=false or JWT_JWKS_FILE")
+		}
+	case DriverPostgres:
+		if cfg.DbConnString == "" {
+			log.Fatal("DB_CONN_STRING is required by the postgres storage driver")
+		}
+	default:
+		log.Fatalf("unknown storage driver %q, must be memory or postgres", cfg.StorageDriver)
+	}
+
+	if cfg.RateLimitStore != DriverMemory && cfg.RateLimitStore != DriverPostgres {
+		log.Fatalf("unknown rate limit store %q, must be memory or postgres", cfg.RateLimitStore)
+	}
+	if cfg.RateLimitStore == DriverPostgres && cfg.StorageDriver != DriverPostgres {
+		log.Fatal("postgres rate limit store needs the postgres storage driver")
+	}
+	for _, limit := range []string{cfg.RateLimitRead, cfg.RateLimitWrite, cfg.RateLimitDelete, cfg.RateLimitAuth} {
+		if _, err := tokenbucket.ParseLimit(limit); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if _, err := clientip.ParseTrusted(cfg.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
+
+	// the JWKS watcher ticker panics on intervals that are not positive
+	if cfg.JWKSReload <= 0 {
+		log.Fatalf("invalid JWKS reload interval %s, must be positive", cfg.JWKSReload)
+	}
+
+	// the purge ticker panics on intervals that are not positive
+	if cfg.PurgeInterval <= 0 {
+		log.Fatalf("invalid purge interval %s, must be positive", cfg.PurgeInterval)
+	}
+	if cfg.TrashRetention < 0 {
+		log.Fatalf("invalid trash retention %s, must not be negative", cfg.TrashRetention)
+	}
+
+	if cfg.AccessLogSample < 0 || cfg.AccessLogSample > 1 {
+		log.Fatalf("invalid access log sample %v, must be between 0 and 1", cfg.AccessLogSample)
+	}
+
 	return &cfg
 }
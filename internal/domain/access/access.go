@@ -0,0 +1,101 @@
+// Package access describes who makes a request and what they may do.
+// Transports put the Principal into the context, services check it, so
+// every transport follows the same rules.
+package access
+
+import (
+	"app/internal/domain/errcode"
+	"context"
+	"fmt"
+	"slices"
+)
+
+type Role string
+
+// Roles from the least to the most privileged. Readers only read,
+// contributors add quotes and change their own ones, editors change every
+// quote and curate the daily quote, admins do everything.
+const (
+	Reader      Role = "reader"
+	Contributor Role = "contributor"
+	Editor      Role = "editor"
+	Admin       Role = "admin"
+)
+
+var Roles = []Role{Reader, Contributor, Editor, Admin}
+
+var (
+	ErrRoleRequired = errcode.New(errcode.InsufficientRole, "Your role does not allow this action")
+	ErrNotOwner     = errcode.New(errcode.NotQuoteOwner, "Contributors may only change their own quotes")
+)
+
+// ParseRole returns the role named s.
+func ParseRole(s string) (Role, bool) {
+	role := Role(s)
+	return role, slices.Contains(Roles, role)
+}
+
+// AtLeast reports whether r is as privileged as other or more.
+func (r Role) AtLeast(other Role) bool {
+	return slices.Index(Roles, r) >= slices.Index(Roles, other)
+}
+
+// Principal is the caller of a request. Subject identifies the caller across
+// transports: the user name of an API key or the sub claim of a JWT.
+type Principal struct {
+	Subject string
+	Role    Role
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the caller of ctx. There is none for background jobs,
+// command line tools and servers running without authentication.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Require returns ErrRoleRequired unless the caller has at least role.
+// Requests without a caller are trusted.
+func Require(ctx context.Context, role Role) error {
+	p, ok := FromContext(ctx)
+	if !ok || p.Role.AtLeast(role) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s %q needs role %s", ErrRoleRequired, p.Role, p.Subject, role)
+}
+
+// RequireOwner returns an error unless the caller may change a quote of
+// owner: editors and admins change every quote, contributors only theirs.
+// Quotes without an owner were added before ownership and belong to nobody.
+func RequireOwner(ctx context.Context, owner string) error {
+	p, ok := FromContext(ctx)
+	if !ok || p.Role.AtLeast(Editor) {
+		return nil
+	}
+
+	if err := Require(ctx, Contributor); err != nil {
+		return err
+	}
+
+	if owner == "" || owner != p.Subject {
+		return fmt.Errorf("%w: %q is not the owner", ErrNotOwner, p.Subject)
+	}
+
+	return nil
+}
+
+// Owner returns the subject recorded as the owner of quotes the caller adds.
+func Owner(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+
+	return ""
+}
@@ -0,0 +1,86 @@
+// Package errcode gives domain errors stable machine readable codes.
+// Sentinel errors of quteos and storage that clients can act upon are
+// *Error values, everything else is an internal error. The HTTP mapping
+// lives in package apierr.
+package errcode
+
+import "errors"
+
+type Code string
+
+const (
+	Internal             Code = "INTERNAL"
+	ValidationFailed     Code = "VALIDATION_FAILED"
+	InvalidArgument      Code = "INVALID_ARGUMENT"
+	InvalidBody          Code = "INVALID_BODY"
+	UnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	NotAcceptable        Code = "NOT_ACCEPTABLE"
+	Unauthenticated      Code = "UNAUTHENTICATED"
+	PermissionDenied     Code = "PERMISSION_DENIED"
+	InsufficientRole     Code = "INSUFFICIENT_ROLE"
+	NotQuoteOwner        Code = "NOT_QUOTE_OWNER"
+	PayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	RateLimited          Code = "RATE_LIMITED"
+	QuoteNotFound        Code = "QUOTE_NOT_FOUND"
+	AuthorNotFound       Code = "AUTHOR_NOT_FOUND"
+	PinNotFound          Code = "DAILY_PIN_NOT_FOUND"
+	NoQuotes             Code = "NO_QUOTES"
+	DuplicateQuote       Code = "DUPLICATE_QUOTE"
+	SimilarQuote         Code = "SIMILAR_QUOTE"
+	VersionConflict      Code = "VERSION_CONFLICT"
+	PreconditionRequired Code = "PRECONDITION_REQUIRED"
+	ImportRejected       Code = "IMPORT_REJECTED"
+)
+
+// Error is an error clients can act upon. Msg is safe to show to them.
+type Error struct {
+	Code Code
+	Msg  string
+}
+
+func New(code Code, msg string) *Error {
+	return &Error{Code: code, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// Of returns the first coded error in the chain of err, or an Internal one.
+func Of(err error) *Error {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded
+	}
+
+	return &Error{Code: Internal, Msg: "internal server error"}
+}
+
+// Detailer is implemented by errors that carry data for clients, such as
+// the id of the existing quote of a duplicate.
+type Detailer interface {
+	Details() any
+}
+
+type detailed struct {
+	error
+	details any
+}
+
+func (d *detailed) Details() any  { return d.details }
+func (d *detailed) Unwrap() error { return d.error }
+
+// WithDetails attaches data for clients to err.
+func WithDetails(err error, details any) error {
+	return &detailed{error: err, details: details}
+}
+
+// DetailsOf returns the data attached to err, if any.
+func DetailsOf(err error) any {
+	var d Detailer
+	if errors.As(err, &d) {
+		return d.Details()
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+package models
+
+type Author struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	QuotesCount int    `json:"quotes_count"`
+}
@@ -1,6 +1,9 @@
 package models
 
+// Quote lengths are counted in grapheme clusters, see package unitext for
+// the gmin, gmax and visible rules.
 type Quote struct {
-	Author string `json:"author" validate:"required,min=3,max=100,printascii"`
-	Text   string `json:"quote" validate:"required,min=3,max=500,printascii"`
+	Author string   `json:"author" validate:"required,gmin=3,gmax=100,visible"`
+	Text   string   `json:"quote" validate:"required,gmin=3,gmax=500,visible"`
+	Tags   []string `json:"tags,omitempty" validate:"max=10,dive,gmin=2,gmax=30,visible"`
 }
@@ -0,0 +1,6 @@
+package models
+
+type Tag struct {
+	Name        string `json:"name"`
+	QuotesCount int    `json:"quotes_count"`
+}
@@ -0,0 +1,138 @@
+// Package apierr is the single place errors are turned into HTTP responses.
+package apierr
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/lib/api/response"
+	"app/internal/lib/validation"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// requestIDHeader is set on the response by the requestID middleware before
+// handlers run, errors repeat it so clients can quote it.
+const requestIDHeader = "X-Request-ID"
+
+var statuses = map[errcode.Code]int{
+	errcode.ValidationFailed:     http.StatusBadRequest,
+	errcode.InvalidArgument:      http.StatusBadRequest,
+	errcode.InvalidBody:          http.StatusBadRequest,
+	errcode.UnsupportedMediaType: http.StatusUnsupportedMediaType,
+	errcode.NotAcceptable:        http.StatusNotAcceptable,
+	errcode.Unauthenticated:      http.StatusUnauthorized,
+	errcode.PermissionDenied:     http.StatusForbidden,
+	errcode.InsufficientRole:     http.StatusForbidden,
+	errcode.NotQuoteOwner:        http.StatusForbidden,
+	errcode.PayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	errcode.RateLimited:          http.StatusTooManyRequests,
+	errcode.QuoteNotFound:        http.StatusNotFound,
+	errcode.AuthorNotFound:       http.StatusNotFound,
+	errcode.PinNotFound:          http.StatusNotFound,
+	errcode.NoQuotes:             http.StatusNotFound,
+	errcode.DuplicateQuote:       http.StatusConflict,
+	errcode.SimilarQuote:         http.StatusConflict,
+	errcode.VersionConflict:      http.StatusPreconditionFailed,
+	errcode.PreconditionRequired: http.StatusPreconditionRequired,
+	errcode.ImportRejected:       http.StatusUnprocessableEntity,
+}
+
+// Status returns the HTTP status of code, 500 for unknown codes.
+func Status(code errcode.Code) int {
+	if status, ok := statuses[code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Problem is an RFC 7807 problem details document. Code, Errors, Details and
+// RequestID are extension members with the same meaning as in response.Response.
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      errcode.Code      `json:"code"`
+	Errors    validation.Errors `json:"errors,omitempty"`
+	Details   any               `json:"details,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// Write answers r with the status and the stable code of err. Clients that
+// accept application/problem+json get RFC 7807 problem details, others get
+// response.Response. Internal errors are logged and not disclosed.
+func Write(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	coded := errcode.Of(err)
+	status := Status(coded.Code)
+
+	msg := coded.Msg
+	if status == http.StatusInternalServerError {
+		log.ErrorContext(r.Context(), "request failed", "error", err, "code", coded.Code, "status", status)
+
+		msg = "Internal server error"
+	} else {
+		log.InfoContext(r.Context(), "request rejected", "error", err, "code", coded.Code, "status", status)
+	}
+
+	var fieldErrs validation.Errors
+	errors.As(err, &fieldErrs)
+
+	details := errcode.DetailsOf(err)
+	requestID := w.Header().Get(requestIDHeader)
+
+	if wantsProblem(r) {
+		w.Header().Set("Content-Type", ProblemContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(Problem{
+			Type:      "about:blank",
+			Title:     http.StatusText(status),
+			Status:    status,
+			Detail:    msg,
+			Instance:  r.URL.Path,
+			Code:      coded.Code,
+			Errors:    fieldErrs,
+			Details:   details,
+			RequestID: requestID,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response.Response{
+		Status:    response.StatusError,
+		Code:      string(coded.Code),
+		Error:     msg,
+		Errors:    fieldErrs,
+		Payload:   details,
+		RequestID: requestID,
+	})
+}
+
+// wantsProblem reports whether the Accept header lists problem details.
+func wantsProblem(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrInvalidBody rejects request bodies that cannot be decoded.
+var ErrInvalidBody = errcode.New(errcode.InvalidBody, "Invalid request body")
+
+// InvalidArgument rejects a malformed query parameter or path variable.
+func InvalidArgument(msg string) error {
+	return errcode.New(errcode.InvalidArgument, msg)
+}
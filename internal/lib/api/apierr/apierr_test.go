@@ -0,0 +1,100 @@
+package apierr
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/lib/api/response"
+	"app/internal/lib/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var log = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestWrite(t *testing.T) {
+	notFound := errcode.New(errcode.QuoteNotFound, "quote not found")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		msg    string
+	}{
+		{"coded", notFound, http.StatusNotFound, "QUOTE_NOT_FOUND", "quote not found"},
+		{"wrapped", fmt.Errorf("failed to get quote: %w", notFound), http.StatusNotFound, "QUOTE_NOT_FOUND", "quote not found"},
+		{"internal", errors.New("connection refused"), http.StatusInternalServerError, "INTERNAL", "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set("X-Request-ID", "req-1")
+			Write(w, httptest.NewRequest(http.MethodGet, "/quotes/1", nil), log, tt.err)
+
+			if w.Code != tt.status {
+				t.Errorf("Write() status = %d, expected %d", w.Code, tt.status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Write() Content-Type = %q, expected application/json", ct)
+			}
+
+			var resp response.Response
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Status != response.StatusError || resp.Code != tt.code || resp.Error != tt.msg {
+				t.Errorf("Write() = %+v, expected code %s and error %q", resp, tt.code, tt.msg)
+			}
+			if resp.RequestID != "req-1" {
+				t.Errorf("Write() request_id = %q, expected the ID of the request", resp.RequestID)
+			}
+		})
+	}
+}
+
+func TestWrite_Problem(t *testing.T) {
+	errs := validation.Errors{{Field: "author", Code: validation.CodeRequired, Message: "author is required"}}
+	err := errcode.WithDetails(fmt.Errorf("%w: %w", errcode.New(errcode.ValidationFailed, "validation failed"), errs), map[string]int{"id": 1})
+
+	r := httptest.NewRequest(http.MethodPost, "/quotes", nil)
+	r.Header.Set("Accept", "application/json, application/problem+json;q=0.9")
+
+	w := httptest.NewRecorder()
+	Write(w, r, log, err)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Write() status = %d, expected %d", w.Code, http.StatusBadRequest)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Write() Content-Type = %q, expected %s", ct, ProblemContentType)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if p.Status != http.StatusBadRequest || p.Code != errcode.ValidationFailed || p.Instance != "/quotes" || p.Detail != "validation failed" {
+		t.Errorf("Write() = %+v, expected a validation problem of /quotes", p)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "author" {
+		t.Errorf("Write() errors = %v, expected the error of author", p.Errors)
+	}
+	if p.Details == nil {
+		t.Error("Write() details are missing")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	if s := Status(errcode.VersionConflict); s != http.StatusPreconditionFailed {
+		t.Errorf("Status(VERSION_CONFLICT) = %d, expected %d", s, http.StatusPreconditionFailed)
+	}
+	if s := Status("UNKNOWN"); s != http.StatusInternalServerError {
+		t.Errorf("Status(UNKNOWN) = %d, expected %d", s, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,43 @@
+package etag
+
+import (
+	"app/internal/domain/errcode"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrMissing = errcode.New(errcode.PreconditionRequired, "If-Match header is required")
+	ErrInvalid = errcode.New(errcode.InvalidArgument, "If-Match header must contain a quote ETag")
+)
+
+// Format renders a quote version as a strong ETag value.
+func Format(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// Set writes the ETag header for the given version.
+func Set(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", Format(version))
+}
+
+// IfMatch extracts the expected version from the If-Match request header.
+func IfMatch(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, ErrMissing
+	}
+
+	// weak validators are not allowed for If-Match comparison
+	if strings.HasPrefix(value, "W/") || len(value) < 3 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, ErrInvalid
+	}
+
+	version, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || version <= 0 {
+		return 0, ErrInvalid
+	}
+
+	return version, nil
+}
@@ -0,0 +1,25 @@
+// Package link builds RFC 8288 Link headers for paginated responses.
+package link
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// SetNext adds a rel="next" link pointing at the same resource with the given cursor.
+// The other query parameters of the request are kept.
+func SetNext(w http.ResponseWriter, r *http.Request, cursor string) {
+	if cursor == "" {
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("cursor", cursor)
+
+	next := url.URL{
+		Path:     r.URL.Path,
+		RawQuery: query.Encode(),
+	}
+
+	w.Header().Add("Link", "<"+next.String()+`>; rel="next"`)
+}
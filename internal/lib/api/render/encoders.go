@@ -0,0 +1,109 @@
+package render
+
+import (
+	"app/internal/lib/quoteio"
+	"app/internal/storage"
+	"encoding/xml"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Text writes one quote per line as "text" — Author, for terminals and
+// MOTD scripts.
+type Text struct{}
+
+func (Text) MediaType() string { return "text/plain" }
+
+func (Text) Encode(w io.Writer, quotes []*storage.StorageQuote) error {
+	var b strings.Builder
+	for _, q := range quotes {
+		b.WriteString(`"` + q.Text + `" — ` + q.Author + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlPage = template.Must(template.New("quotes").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Quotes</title>
+</head>
+<body>
+{{- range .}}
+<figure>
+<blockquote>{{.Text}}</blockquote>
+<figcaption>{{.Author}}</figcaption>
+</figure>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML writes a page with a figure per quote.
+type HTML struct{}
+
+func (HTML) MediaType() string { return "text/html" }
+
+func (HTML) Encode(w io.Writer, quotes []*storage.StorageQuote) error {
+	return htmlPage.Execute(w, quotes)
+}
+
+type xmlQuotes struct {
+	XMLName xml.Name   `xml:"quotes"`
+	Quotes  []xmlQuote `xml:"quote"`
+}
+
+type xmlQuote struct {
+	ID     int      `xml:"id,attr,omitempty"`
+	Author string   `xml:"author"`
+	Text   string   `xml:"text"`
+	Tags   []string `xml:"tags>tag,omitempty"`
+}
+
+// XML writes a quotes element with a quote element per quote.
+type XML struct{}
+
+func (XML) MediaType() string { return "application/xml" }
+
+func (XML) Encode(w io.Writer, quotes []*storage.StorageQuote) error {
+	doc := xmlQuotes{Quotes: make([]xmlQuote, len(quotes))}
+	for i, q := range quotes {
+		doc.Quotes[i] = xmlQuote{ID: q.Id, Author: q.Author, Text: q.Text, Tags: q.Tags}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// CSV writes the columns of the export, so the document can be imported back.
+type CSV struct{}
+
+func (CSV) MediaType() string { return "text/csv" }
+
+func (CSV) Encode(w io.Writer, quotes []*storage.StorageQuote) error {
+	out, err := quoteio.NewWriter(quoteio.FormatCSV, w)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range quotes {
+		if err := out.Write(&q.Quote); err != nil {
+			return err
+		}
+	}
+
+	return out.Close()
+}
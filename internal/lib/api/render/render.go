@@ -0,0 +1,165 @@
+// Package render writes quotes in the media type a client asks for with the
+// Accept header or the format query parameter. JSON stays the default and is
+// written by handlers with response.Response, other formats are written by
+// encoders registered here.
+package render
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/storage"
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSON is the default format, it has no encoder.
+const JSON = "json"
+
+var ErrNotAcceptable = errcode.New(errcode.NotAcceptable, "Response format is not supported, use json, text, html, xml or csv")
+
+// Encoder writes a list of quotes. A single quote is written as a list of one.
+type Encoder interface {
+	MediaType() string // such as text/plain, without parameters
+	Encode(w io.Writer, quotes []*storage.StorageQuote) error
+}
+
+// encoders by format name, formats lists the names in order of preference
+// for wildcards such as text/*
+var (
+	encoders = map[string]Encoder{}
+	formats  []string
+)
+
+func init() {
+	Register("text", Text{})
+	Register("html", HTML{})
+	Register("xml", XML{})
+	Register("csv", CSV{})
+}
+
+// Register adds or replaces the encoder of format.
+func Register(format string, enc Encoder) {
+	if _, ok := encoders[format]; !ok {
+		formats = append(formats, format)
+	}
+	encoders[format] = enc
+}
+
+// Negotiate picks the response format of r. The format query parameter wins
+// over the Accept header, a missing header means JSON. Because the response
+// depends on the Accept header, it is added to Vary.
+func Negotiate(w http.ResponseWriter, r *http.Request) (string, error) {
+	w.Header().Add("Vary", "Accept")
+
+	if format := r.URL.Query().Get("format"); format != "" {
+		if _, ok := encoders[format]; ok || format == JSON {
+			return format, nil
+		}
+		return "", ErrNotAcceptable
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return JSON, nil
+	}
+
+	for _, mediaType := range acceptedTypes(accept) {
+		if format, ok := match(mediaType); ok {
+			return format, nil
+		}
+	}
+
+	return "", ErrNotAcceptable
+}
+
+// Write encodes quotes in format with status 200. Nothing is written when
+// encoding fails, so the error can still be answered.
+func Write(w http.ResponseWriter, format string, quotes []*storage.StorageQuote) error {
+	enc, ok := encoders[format]
+	if !ok {
+		return ErrNotAcceptable
+	}
+
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, quotes); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", enc.MediaType()+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// acceptedTypes returns media ranges of an Accept header ordered by quality,
+// ranges with q=0 are left out.
+func acceptedTypes(accept string) []string {
+	type mediaRange struct {
+		mediaType string
+		q         float64
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, mediaRange{mediaType, q})
+	}
+
+	slices.SortStableFunc(ranges, func(a, b mediaRange) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+
+	types := make([]string, len(ranges))
+	for i, r := range ranges {
+		types[i] = r.mediaType
+	}
+
+	return types
+}
+
+// match returns the format of a media range. JSON based types such as
+// application/problem+json ask for JSON, errors are then problem details.
+func match(mediaType string) (string, bool) {
+	switch mediaType {
+	case "*/*", "application/*", "application/json":
+		return JSON, true
+	}
+
+	if strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json") {
+		return JSON, true
+	}
+
+	prefix, wildcard := strings.CutSuffix(mediaType, "*")
+	for _, format := range formats {
+		encType := encoders[format].MediaType()
+		if encType == mediaType || wildcard && strings.HasPrefix(encType, prefix) {
+			return format, true
+		}
+	}
+
+	return "", false
+}
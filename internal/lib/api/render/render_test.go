@@ -0,0 +1,95 @@
+package render
+
+import (
+	"app/internal/domain/models"
+	"app/internal/storage"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		accept   string
+		expected string
+		err      error
+	}{
+		{name: "no header", url: "/quotes", expected: JSON},
+		{name: "json", url: "/quotes", accept: "application/json", expected: JSON},
+		{name: "any", url: "/quotes", accept: "*/*", expected: JSON},
+		{name: "problem details", url: "/quotes/100500", accept: "application/problem+json", expected: JSON},
+		{name: "json suffix", url: "/quotes", accept: "application/vnd.quotes+json", expected: JSON},
+		{name: "text", url: "/quotes", accept: "text/plain", expected: "text"},
+		{name: "text wildcard", url: "/quotes", accept: "text/*", expected: "text"},
+		{name: "quality", url: "/quotes", accept: "text/html;q=0.5, application/xml", expected: "xml"},
+		{name: "browser", url: "/quotes", accept: "text/html,application/xhtml+xml,*/*;q=0.8", expected: "html"},
+		{name: "refused", url: "/quotes", accept: "text/csv;q=0, image/png", err: ErrNotAcceptable},
+		{name: "query wins", url: "/quotes?format=csv", accept: "application/json", expected: "csv"},
+		{name: "query json", url: "/quotes?format=json", accept: "text/plain", expected: JSON},
+		{name: "unknown query", url: "/quotes?format=yaml", err: ErrNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			format, err := Negotiate(w, r)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Negotiate() error = %v, expected %v", err, tt.err)
+			}
+			if format != tt.expected {
+				t.Errorf("Negotiate() = %q, expected %q", format, tt.expected)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("Negotiate() Vary = %q, expected Accept", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	quotes := []*storage.StorageQuote{
+		{Id: 1, Quote: models.Quote{Author: "Confucius", Text: "Life is simple", Tags: []string{"life"}}},
+		{Id: 2, Quote: models.Quote{Author: "Tom & Jerry", Text: "<b>Cheese</b>"}},
+	}
+
+	tests := []struct {
+		format      string
+		contentType string
+		contains    []string
+	}{
+		{"text", "text/plain; charset=utf-8", []string{"\"Life is simple\" — Confucius\n", "\"<b>Cheese</b>\" — Tom & Jerry\n"}},
+		{"html", "text/html; charset=utf-8", []string{"<blockquote>Life is simple</blockquote>", "&lt;b&gt;Cheese&lt;/b&gt;", "Tom &amp; Jerry"}},
+		{"xml", "application/xml; charset=utf-8", []string{`<quote id="1">`, "<tags>", "<tag>life</tag>", "&lt;b&gt;Cheese&lt;/b&gt;"}},
+		{"csv", "text/csv; charset=utf-8", []string{"quote,author,tags\n", "Life is simple,Confucius,life\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := Write(w, tt.format, quotes); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Write() status = %d, expected %d", w.Code, http.StatusOK)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Write() Content-Type = %q, expected %q", ct, tt.contentType)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(w.Body.String(), s) {
+					t.Errorf("Write() = %q, expected it to contain %q", w.Body.String(), s)
+				}
+			}
+		})
+	}
+}
@@ -1,16 +1,18 @@
 package response
 
 import (
-	"fmt"
-	"strings"
-
-	"github.com/go-playground/validator/v10"
+	"app/internal/lib/validation"
 )
 
 type Response struct {
-	Status  string `json:"status"`
-	Error   string `json:"error,omitempty"`
-	Payload any    `json:"payload,omitempty"`
+	Status     string                  `json:"status"`
+	Code       string                  `json:"code,omitempty"` // stable error code, see package errcode
+	Error      string                  `json:"error,omitempty"`
+	Errors     []validation.FieldError `json:"errors,omitempty"`
+	RequestID  string                  `json:"request_id,omitempty"` // of failed requests, to find them in logs
+	Payload    any                     `json:"payload,omitempty"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	Total      *int                    `json:"total,omitempty"`
 }
 
 const (
@@ -31,30 +33,19 @@ func OKWithPayload(payload any) Response {
 	}
 }
 
-func Error(err string) Response {
+// Page is a successful response with one page of a paginated list.
+func Page(payload any, nextCursor string, total *int) Response {
 	return Response{
-		Status: StatusError,
-		Error:  err,
+		Status:     StatusOK,
+		Payload:    payload,
+		NextCursor: nextCursor,
+		Total:      total,
 	}
 }
 
-func ValidationError(errs validator.ValidationErrors) Response {
-	var errMsgs []string
-
-	for _, err := range errs {
-		switch err.ActualTag() {
-		case "required":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is missed", err.Field()))
-		case "url":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid url", err.Field()))
-		default:
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
-
-		}
-	}
-
+func Error(err string) Response {
 	return Response{
 		Status: StatusError,
-		Error:  strings.Join(errMsgs, ", "),
+		Error:  err,
 	}
 }
@@ -0,0 +1,104 @@
+// Package clientip finds the address of the client behind trusted proxies.
+package clientip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+var ErrInvalidProxy = errors.New("invalid trusted proxy, must be an IP address or a CIDR range")
+
+// ParseTrusted parses proxies given as addresses or CIDR ranges.
+func ParseTrusted(proxies []string) ([]netip.Prefix, error) {
+	trusted := make([]netip.Prefix, 0, len(proxies))
+
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		if prefix, err := netip.ParsePrefix(p); err == nil {
+			trusted = append(trusted, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidProxy, p)
+		}
+		trusted = append(trusted, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+
+	return trusted, nil
+}
+
+// FromRequest returns the address of the client of r. X-Forwarded-For is only
+// believed when the request comes from a trusted proxy: the header is read
+// from the right and the first address that is not a trusted proxy is the
+// client, anything left of it could have been sent by the client itself.
+func FromRequest(r *http.Request, trusted []netip.Prefix) string {
+	remote := remoteAddr(r)
+	if !remote.IsValid() {
+		return r.RemoteAddr
+	}
+
+	if !isTrusted(remote, trusted) {
+		return remote.String()
+	}
+
+	client := remote
+	forwarded := forwardedFor(r)
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+
+		client = addr.Unmap()
+		if !isTrusted(client, trusted) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+func remoteAddr(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return addr.Unmap()
+}
+
+// forwardedFor returns the addresses of every X-Forwarded-For header in order.
+func forwardedFor(r *http.Request) []string {
+	var addrs []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		addrs = append(addrs, strings.Split(h, ",")...)
+	}
+
+	return addrs
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
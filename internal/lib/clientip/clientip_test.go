@@ -0,0 +1,48 @@
+package clientip
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromRequest(t *testing.T) {
+	trusted, err := ParseTrusted([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParseTrusted() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		expected  string
+	}{
+		{name: "direct", remote: "203.0.113.7:5000", expected: "203.0.113.7"},
+		{name: "spoofed by an untrusted client", remote: "203.0.113.7:5000", forwarded: []string{"1.1.1.1"}, expected: "203.0.113.7"},
+		{name: "behind a proxy", remote: "10.0.0.2:5000", forwarded: []string{"198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "spoofed behind a proxy", remote: "10.0.0.2:5000", forwarded: []string{"1.1.1.1, 198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "chain of proxies", remote: "10.0.0.2:5000", forwarded: []string{"198.51.100.1, 192.168.1.1", "10.1.2.3"}, expected: "198.51.100.1"},
+		{name: "only proxies", remote: "10.0.0.2:5000", forwarded: []string{"10.0.0.3"}, expected: "10.0.0.3"},
+		{name: "garbage", remote: "10.0.0.2:5000", forwarded: []string{"unknown"}, expected: "10.0.0.2"},
+		{name: "ipv6", remote: "[2001:db8::1]:5000", expected: "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, h := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", h)
+			}
+
+			if got := FromRequest(r, trusted); got != tt.expected {
+				t.Errorf("FromRequest() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseTrusted([]string{"proxy.local"}); !errors.Is(err, ErrInvalidProxy) {
+		t.Errorf("ParseTrusted() error = %v, expected %v", err, ErrInvalidProxy)
+	}
+}
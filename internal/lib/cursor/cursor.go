@@ -0,0 +1,38 @@
+// Package cursor encodes keyset pagination positions as opaque tokens.
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last item of the previous page.
+// Rank is only set for lists ordered by match quality.
+type Cursor struct {
+	ID   int     `json:"id"`
+	Rank float64 `json:"r,omitempty"`
+}
+
+func Encode(c Cursor) string {
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func Decode(token string) (Cursor, error) {
+	var c Cursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID <= 0 {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,44 @@
+// Package fingerprint derives the key under which quotes are considered
+// duplicates of each other.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// Of returns the fingerprint of a quote: a hash of its case-folded text and
+// author with every run of spaces and punctuation collapsed into one space.
+func Of(text, author string) string {
+	h := sha256.New()
+	h.Write([]byte(Normalize(text)))
+	h.Write([]byte{0})
+	h.Write([]byte(Normalize(author)))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Normalize lowercases s and keeps only letters and digits, separated by
+// single spaces.
+func Normalize(s string) string {
+	var b strings.Builder
+
+	gap := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			gap = true
+			continue
+		}
+
+		if gap && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		gap = false
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package fingerprint
+
+import "testing"
+
+func TestOf(t *testing.T) {
+	base := Of("Life is simple, but we insist on making it complicated.", "Confucius")
+
+	same := [][2]string{
+		{"life is simple but we insist on making it complicated", "Confucius"},
+		{"  Life is simple -- but we insist on making it complicated!!", "  confucius "},
+		{"LIFE IS SIMPLE,\nBUT WE INSIST ON MAKING IT COMPLICATED", "CONFUCIUS"},
+	}
+	for _, q := range same {
+		if Of(q[0], q[1]) != base {
+			t.Errorf("Of(%q, %q) differs from the original quote", q[0], q[1])
+		}
+	}
+
+	different := [][2]string{
+		{"Life is simple, but we insist on making it complicated.", "Socrates"},
+		{"Life is simple, but we insist on making it complex.", "Confucius"},
+		// text and author are hashed separately
+		{"Life is simple, but we insist on making it complicated. Confucius", ""},
+	}
+	for _, q := range different {
+		if Of(q[0], q[1]) == base {
+			t.Errorf("Of(%q, %q) equals the original quote", q[0], q[1])
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package jwtauth verifies JWTs issued by a trusted gateway against keys of
+// a local JWKS file (RFC 7517). HS256, RS256 and EdDSA (Ed25519) tokens are
+// supported. The file is reloaded when it changes, so keys can be rotated
+// without a restart: add the new key, switch the issuer, remove the old key.
+package jwtauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidKeySet = errors.New("invalid jwks")
+	ErrUnknownKey    = errors.New("no key for token")
+)
+
+// key is a verification key of the set.
+type key struct {
+	id  string
+	kty string // oct, RSA or OKP
+	alg string // optional, restricts the key to one algorithm
+	key any    // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// ktyOf maps supported algorithms to key types.
+var ktyOf = map[string]string{
+	jwt.SigningMethodHS256.Alg(): "oct",
+	jwt.SigningMethodRS256.Alg(): "RSA",
+	jwt.SigningMethodEdDSA.Alg(): "OKP",
+}
+
+// KeySet holds the keys of a JWKS file.
+type KeySet struct {
+	path string
+	log  *slog.Logger
+
+	mu      sync.RWMutex
+	keys    []key
+	modTime time.Time
+}
+
+// Load reads the JWKS file at path.
+func Load(log *slog.Logger, path string) (*KeySet, error) {
+	s := &KeySet{path: path, log: log}
+
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Reload reads the file again if it was modified and reports whether the
+// keys changed. A file that fails to parse leaves the current keys in place.
+func (s *KeySet) Reload() (bool, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+
+	keys, err := parse(raw)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+
+	s.log.Info("JWKS loaded", "path", s.path, "keys", len(keys))
+	return true, nil
+}
+
+// Watch reloads the file on every interval tick until ctx is cancelled.
+func (s *KeySet) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Reload(); err != nil {
+				s.log.Error("failed to reload JWKS, keeping current keys", "error", err)
+			}
+		}
+	}
+}
+
+// keyFunc picks the keys matching the kid and the algorithm of a token. A
+// token without kid is tried against every key of its algorithm.
+func (s *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	alg := t.Method.Alg()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []jwt.VerificationKey
+	for _, k := range s.keys {
+		if kid != "" && k.id != kid {
+			continue
+		}
+		if k.kty != ktyOf[alg] || k.alg != "" && k.alg != alg {
+			continue
+		}
+		keys = append(keys, k.key)
+	}
+
+	switch len(keys) {
+	case 0:
+		return nil, fmt.Errorf("%w: kid %q, alg %s", ErrUnknownKey, kid, alg)
+	case 1:
+		return keys[0], nil
+	}
+
+	return jwt.VerificationKeySet{Keys: keys}, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	K   string `json:"k"` // oct
+	N   string `json:"n"` // RSA
+	E   string `json:"e"`
+	X   string `json:"x"` // OKP
+}
+
+// parse decodes signature keys of a JWKS document, encryption keys are skipped.
+func parse(raw []byte) ([]key, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+
+	keys := make([]key, 0, len(doc.Keys))
+	for i, j := range doc.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+
+		k, err := j.decode()
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %d: %w", ErrInvalidKeySet, i, err)
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+func (j jwk) decode() (key, error) {
+	k := key{id: j.Kid, kty: j.Kty, alg: j.Alg}
+
+	switch j.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(j.K)
+		if err != nil || len(secret) == 0 {
+			return k, errors.New("invalid k")
+		}
+		k.key = secret
+
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil || len(n) == 0 {
+			return k, errors.New("invalid n")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return k, errors.New("invalid e")
+		}
+		k.key = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if j.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return k, errors.New("invalid Ed25519 key")
+		}
+		k.key = ed25519.PublicKey(x)
+
+	default:
+		return k, fmt.Errorf("unsupported kty %q", j.Kty)
+	}
+
+	return k, nil
+}
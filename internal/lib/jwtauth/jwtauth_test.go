@@ -0,0 +1,205 @@
+package jwtauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	b64 = base64.RawURLEncoding.EncodeToString
+)
+
+type signer struct {
+	kid    string
+	method jwt.SigningMethod
+	key    any
+	jwk    map[string]string
+}
+
+func newSigners(t *testing.T) []signer {
+	t.Helper()
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []signer{
+		{"hs", jwt.SigningMethodHS256, secret, map[string]string{"kty": "oct", "kid": "hs", "k": b64(secret)}},
+		{"rs", jwt.SigningMethodRS256, rsaKey, map[string]string{
+			"kty": "RSA", "kid": "rs", "alg": "RS256",
+			"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		}},
+		{"ed", jwt.SigningMethodEdDSA, edKey, map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64(edPub)}},
+	}
+}
+
+func writeJWKS(t *testing.T, path string, signers ...signer) {
+	t.Helper()
+
+	keys := make([]map[string]string, len(signers))
+	for i, s := range signers {
+		keys[i] = s.jwk
+	}
+
+	raw, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s signer) sign(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(s.method, claims)
+	token.Header["kid"] = s.kid
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "alice",
+			Issuer:    "gateway",
+			Audience:  jwt.ClaimStrings{"quotes"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			NotBefore: jwt.NewNumericDate(now.Add(-time.Minute)),
+		},
+		Roles: []string{"quotes.reader"},
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	signers := newSigners(t)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, signers...)
+
+	keys, err := Load(log, path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	v := NewVerifier(keys, "gateway", "quotes")
+
+	for _, s := range signers {
+		t.Run(s.method.Alg(), func(t *testing.T) {
+			claims, err := v.Verify(s.sign(t, validClaims()))
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if claims.Subject != "alice" || len(claims.Roles) != 1 || claims.Roles[0] != "quotes.reader" {
+				t.Errorf("Verify() = %+v, expected subject alice with role quotes.reader", claims)
+			}
+		})
+	}
+
+	hs := signers[0]
+
+	invalid := map[string]func(c *Claims){
+		"expired":      func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) },
+		"not yet":      func(c *Claims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour)) },
+		"no exp":       func(c *Claims) { c.ExpiresAt = nil },
+		"wrong issuer": func(c *Claims) { c.Issuer = "someone" },
+		"wrong aud":    func(c *Claims) { c.Audience = jwt.ClaimStrings{"billing"} },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			claims := validClaims()
+			modify(&claims)
+
+			if _, err := v.Verify(hs.sign(t, claims)); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify() error = %v, expected %v", err, ErrInvalidToken)
+			}
+		})
+	}
+
+	t.Run("algorithm of another key", func(t *testing.T) {
+		// an HS256 token claiming the kid of the RSA key
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+		token.Header["kid"] = "rs"
+		signed, _ := token.SignedString([]byte("0123456789abcdef0123456789abcdef"))
+
+		if _, err := v.Verify(signed); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Verify() error = %v, expected %v", err, ErrInvalidToken)
+		}
+	})
+}
+
+func TestKeySet_Reload(t *testing.T) {
+	signers := newSigners(t)
+	oldKey, newKey := signers[0], signers[2]
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, oldKey)
+
+	keys, err := Load(log, path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	v := NewVerifier(keys, "", "")
+
+	if _, err := v.Verify(newKey.sign(t, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify() before rotation error = %v, expected %v", err, ErrInvalidToken)
+	}
+
+	writeJWKS(t, path, newKey)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := keys.Reload(); err != nil || !changed {
+		t.Fatalf("Reload() = %v, %v, expected changed keys", changed, err)
+	}
+
+	if _, err := v.Verify(newKey.sign(t, validClaims())); err != nil {
+		t.Errorf("Verify() with the new key error = %v", err)
+	}
+	if _, err := v.Verify(oldKey.sign(t, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() with the removed key error = %v, expected %v", err, ErrInvalidToken)
+	}
+
+	// a broken file keeps the current keys
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	evenLater := later.Add(time.Second)
+	os.Chtimes(path, evenLater, evenLater)
+
+	if _, err := keys.Reload(); !errors.Is(err, ErrInvalidKeySet) {
+		t.Errorf("Reload() of a broken file error = %v, expected %v", err, ErrInvalidKeySet)
+	}
+	if _, err := v.Verify(newKey.sign(t, validClaims())); err != nil {
+		t.Errorf("Verify() after a failed reload error = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package jwtauth
+
+import (
+	"app/internal/domain/errcode"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errcode.New(errcode.Unauthenticated, "Bearer token is not valid or expired")
+	ErrNoRole       = errcode.New(errcode.PermissionDenied, "Bearer token does not have a role required")
+)
+
+// leeway tolerates clock skew between the gateway and the service.
+const leeway = 30 * time.Second
+
+// Claims are the verified claims of a token, Roles come from the roles claim.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Verifier checks the signature, exp, nbf and, when configured, iss and aud
+// of tokens. Tokens without exp are rejected.
+type Verifier struct {
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys *KeySet, issuer string, audience string) *Verifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{
+			jwt.SigningMethodHS256.Alg(),
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+		}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+
+	return &Verifier{
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}
+}
+
+// Verify returns the claims of a valid token, other tokens are rejected
+// with ErrInvalidToken.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.keys.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return &claims, nil
+}
+
+// IsJWT reports whether a bearer credential looks like a compact JWT rather
+// than an API key.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
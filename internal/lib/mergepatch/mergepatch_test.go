@@ -0,0 +1,70 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace field",
+			doc:   `{"author":"Confucius","quote":"Life is simple"}`,
+			patch: `{"quote":"Life is really simple"}`,
+			want:  `{"author":"Confucius","quote":"Life is really simple"}`,
+		},
+		{
+			name:  "null removes field",
+			doc:   `{"a":"b","c":"d"}`,
+			patch: `{"a":null}`,
+			want:  `{"c":"d"}`,
+		},
+		{
+			name:  "nested objects are merged",
+			doc:   `{"a":{"b":"c","d":"e"}}`,
+			patch: `{"a":{"d":"f"}}`,
+			want:  `{"a":{"b":"c","d":"f"}}`,
+		},
+		{
+			name:  "arrays are replaced",
+			doc:   `{"a":["b"]}`,
+			patch: `{"a":["c","d"]}`,
+			want:  `{"a":["c","d"]}`,
+		},
+		{
+			name:  "non object patch replaces document",
+			doc:   `{"a":"b"}`,
+			patch: `["c"]`,
+			want:  `["c"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply() unexpected error = %v", err)
+			}
+
+			var gotV, wantV any
+			json.Unmarshal(got, &gotV)
+			json.Unmarshal([]byte(tt.want), &wantV)
+
+			if !reflect.DeepEqual(gotV, wantV) {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_InvalidPatch(t *testing.T) {
+	if _, err := Apply([]byte(`{}`), []byte(`{`)); err == nil {
+		t.Error("Apply() expected error for malformed patch")
+	}
+}
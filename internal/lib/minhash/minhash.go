@@ -0,0 +1,67 @@
+// Package minhash estimates the Jaccard similarity of sets from short
+// fixed-size signatures, so that sets do not have to be compared directly.
+package minhash
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// Size is the number of hash functions of a signature. The standard error of
+// the estimate is about 1/sqrt(Size).
+const Size = 128
+
+// Signature keeps the minimal value of every hash function over a set.
+// Signatures of empty sets are nil.
+type Signature []uint64
+
+// Of returns the signature of the set of shingles.
+func Of(shingles map[string]struct{}) Signature {
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	sig := make(Signature, Size)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+
+	for s := range shingles {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		base := h.Sum64()
+
+		for i := range sig {
+			if v := mix(base + uint64(i)*0x9e3779b97f4a7c15); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the sets of two signatures
+// as the share of hash functions with the same minimum.
+func Similarity(a, b Signature) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+
+	return float64(same) / float64(len(a))
+}
+
+// mix is the splitmix64 finalizer, it turns one hash into a family of
+// independent ones when applied to the hash plus a per function offset.
+func mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
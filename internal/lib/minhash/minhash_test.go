@@ -0,0 +1,37 @@
+package minhash
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func set(from, to int) map[string]struct{} {
+	s := make(map[string]struct{})
+	for i := from; i < to; i++ {
+		s[fmt.Sprint(i)] = struct{}{}
+	}
+	return s
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     map[string]struct{}
+		expected float64
+	}{
+		{name: "same sets", a: set(0, 100), b: set(0, 100), expected: 1},
+		{name: "disjoint sets", a: set(0, 100), b: set(100, 200), expected: 0},
+		{name: "half overlap", a: set(0, 150), b: set(50, 200), expected: 0.5},
+		{name: "empty set", a: set(0, 0), b: set(0, 0), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(Of(tt.a), Of(tt.b))
+			if math.Abs(got-tt.expected) > 0.15 {
+				t.Errorf("Similarity() = %.2f, expected about %.2f", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package quoteio reads and writes quotes in the bulk formats: a JSON array,
+// newline delimited JSON and CSV with a quote,author,tags header. Tags are
+// separated by ";" inside a CSV cell.
+package quoteio
+
+import (
+	"errors"
+	"mime"
+	"strings"
+)
+
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format, must be json, ndjson or csv")
+	ErrTooManyRows       = errors.New("too many rows")
+	ErrInvalidDocument   = errors.New("invalid document")
+)
+
+// CSV columns, quote and author are required in a header.
+const (
+	columnQuote  = "quote"
+	columnAuthor = "author"
+	columnTags   = "tags"
+
+	tagSeparator = ";"
+)
+
+var contentTypes = map[string]string{
+	FormatJSON:   "application/json",
+	FormatNDJSON: "application/x-ndjson",
+	FormatCSV:    "text/csv",
+}
+
+// FormatFromContentType maps a Content-Type header to a format.
+func FormatFromContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", ErrUnsupportedFormat
+	}
+
+	switch mediaType {
+	case "application/json":
+		return FormatJSON, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return FormatNDJSON, nil
+	case "text/csv":
+		return FormatCSV, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// ContentType returns the Content-Type of the format.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+func splitTags(cell string) []string {
+	if strings.TrimSpace(cell) == "" {
+		return nil
+	}
+
+	return strings.Split(cell, tagSeparator)
+}
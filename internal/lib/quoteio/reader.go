@@ -0,0 +1,161 @@
+package quoteio
+
+import (
+	"app/internal/domain/models"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Row is one record of an imported document. Err is set when the record
+// itself could not be decoded, other rows are still returned.
+type Row struct {
+	Number int // 1-based position of the record, CSV header excluded
+	Quote  models.Quote
+	Err    error
+}
+
+// Read decodes every record of r. It fails as a whole only when the document
+// is malformed beyond a single record or has more than maxRows records.
+func Read(format string, r io.Reader, maxRows int) ([]Row, error) {
+	switch format {
+	case FormatJSON:
+		return readJSON(r, maxRows)
+	case FormatNDJSON:
+		return readNDJSON(r, maxRows)
+	case FormatCSV:
+		return readCSV(r, maxRows)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+func readJSON(r io.Reader, maxRows int) ([]Row, error) {
+	dec := json.NewDecoder(r)
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("%w: expected a JSON array", ErrInvalidDocument)
+	}
+
+	var rows []Row
+	for dec.More() {
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooManyRows, maxRows)
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+		}
+
+		rows = append(rows, decodeRow(len(rows)+1, raw))
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	return rows, nil
+}
+
+func readNDJSON(r io.Reader, maxRows int) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []Row
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooManyRows, maxRows)
+		}
+
+		rows = append(rows, decodeRow(len(rows)+1, line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	return rows, nil
+}
+
+func decodeRow(number int, raw []byte) Row {
+	row := Row{Number: number}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&row.Quote); err != nil {
+		row.Err = fmt.Errorf("invalid JSON object: %w", err)
+	}
+
+	return row
+}
+
+func readCSV(r io.Reader, maxRows int) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing CSV header", ErrInvalidDocument)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	quoteCol, okQuote := columns[columnQuote]
+	authorCol, okAuthor := columns[columnAuthor]
+	if !okQuote || !okAuthor {
+		return nil, fmt.Errorf("%w: CSV header must have quote and author columns", ErrInvalidDocument)
+	}
+	tagsCol, okTags := columns[columnTags]
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooManyRows, maxRows)
+		}
+
+		row := Row{Number: len(rows) + 1}
+
+		switch {
+		case err != nil:
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+			}
+			row.Err = fmt.Errorf("invalid CSV record: %w", err)
+
+		case len(record) != len(header):
+			row.Err = fmt.Errorf("invalid CSV record: %d fields, expected %d", len(record), len(header))
+
+		default:
+			row.Quote.Text = record[quoteCol]
+			row.Quote.Author = record[authorCol]
+			if okTags {
+				row.Quote.Tags = splitTags(record[tagsCol])
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,114 @@
+package quoteio
+
+import (
+	"app/internal/domain/models"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		doc    string
+		quotes []string // text of each row, "!" marks a row error
+	}{
+		{
+			name:   "json array",
+			format: FormatJSON,
+			doc:    `[{"quote":"Life is simple","author":"Confucius","tags":["life"]}, {"quote":1}]`,
+			quotes: []string{"Life is simple", "!"},
+		},
+		{
+			name:   "ndjson skips blank lines",
+			format: FormatNDJSON,
+			doc:    "{\"quote\":\"Know thyself\",\"author\":\"Socrates\"}\n\nnot json\n",
+			quotes: []string{"Know thyself", "!"},
+		},
+		{
+			name:   "csv by header names",
+			format: FormatCSV,
+			doc:    "author,quote\nSocrates,Know thyself\nbroken\n",
+			quotes: []string{"Know thyself", "!"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(tt.format, strings.NewReader(tt.doc), 10)
+			if err != nil {
+				t.Fatalf("Read() unexpected error = %v", err)
+			}
+			if len(rows) != len(tt.quotes) {
+				t.Fatalf("Read() returned %d rows, expected %d", len(rows), len(tt.quotes))
+			}
+
+			for i, row := range rows {
+				if row.Number != i+1 {
+					t.Errorf("row %d has number %d", i, row.Number)
+				}
+				if tt.quotes[i] == "!" {
+					if row.Err == nil {
+						t.Errorf("row %d = %v, expected an error", i, row.Quote)
+					}
+					continue
+				}
+				if row.Err != nil || row.Quote.Text != tt.quotes[i] {
+					t.Errorf("row %d = %v, %v, expected %q", i, row.Quote, row.Err, tt.quotes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRead_Limits(t *testing.T) {
+	if _, err := Read(FormatNDJSON, strings.NewReader("{}\n{}\n"), 1); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("Read() error = %v, expected %v", err, ErrTooManyRows)
+	}
+
+	if _, err := Read(FormatCSV, strings.NewReader("text,who\n"), 1); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Read() error = %v, expected %v", err, ErrInvalidDocument)
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	quotes := []models.Quote{
+		{Text: "Life is simple, \"really\"", Author: "Confucius", Tags: []string{"life", "wisdom"}},
+		{Text: "Know thyself", Author: "Socrates"},
+	}
+
+	for _, format := range []string{FormatJSON, FormatNDJSON, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := NewWriter(format, &buf)
+			if err != nil {
+				t.Fatalf("NewWriter() unexpected error = %v", err)
+			}
+			for i := range quotes {
+				if err := w.Write(&quotes[i]); err != nil {
+					t.Fatalf("Write() unexpected error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() unexpected error = %v", err)
+			}
+
+			rows, err := Read(format, &buf, 10)
+			if err != nil {
+				t.Fatalf("Read() of %q unexpected error = %v", buf.String(), err)
+			}
+			if len(rows) != len(quotes) {
+				t.Fatalf("Read() returned %d rows, expected %d", len(rows), len(quotes))
+			}
+			for i, row := range rows {
+				if row.Err != nil || !reflect.DeepEqual(row.Quote, quotes[i]) {
+					t.Errorf("row %d = %v, %v, expected %v", i, row.Quote, row.Err, quotes[i])
+				}
+			}
+		})
+	}
+}
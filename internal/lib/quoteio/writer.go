@@ -0,0 +1,90 @@
+package quoteio
+
+import (
+	"app/internal/domain/models"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Writer encodes quotes one by one in a format that Read accepts back.
+type Writer struct {
+	format string
+	w      io.Writer
+	csv    *csv.Writer
+	count  int
+}
+
+// NewWriter starts a document, for CSV it writes the header.
+func NewWriter(format string, w io.Writer) (*Writer, error) {
+	writer := &Writer{format: format, w: w}
+
+	switch format {
+	case FormatJSON, FormatNDJSON:
+
+	case FormatCSV:
+		writer.csv = csv.NewWriter(w)
+		if err := writer.csv.Write([]string{columnQuote, columnAuthor, columnTags}); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	return writer, nil
+}
+
+func (w *Writer) Write(q *models.Quote) error {
+	defer func() { w.count++ }()
+
+	switch w.format {
+	case FormatCSV:
+		return w.csv.Write([]string{q.Text, q.Author, strings.Join(q.Tags, tagSeparator)})
+
+	case FormatJSON:
+		sep := ","
+		if w.count == 0 {
+			sep = "["
+		}
+		if _, err := io.WriteString(w.w, sep); err != nil {
+			return err
+		}
+
+		raw, err := json.Marshal(q)
+		if err != nil {
+			return err
+		}
+		_, err = w.w.Write(raw)
+		return err
+	}
+
+	// json.Encoder terminates every value with a newline
+	return json.NewEncoder(w.w).Encode(q)
+}
+
+// Flush writes buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+
+	return nil
+}
+
+// Close ends the document. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.format == FormatJSON {
+		end := "]"
+		if w.count == 0 {
+			end = "[]"
+		}
+		if _, err := io.WriteString(w.w, end); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,62 @@
+package tokenbucket
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore forgets keys with full buckets.
+const sweepInterval = time.Minute
+
+type entry struct {
+	Bucket
+	per time.Duration
+}
+
+// MemoryStore keeps buckets in the process, each replica limits on its own.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) TakeToken(ctx context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	e, ok := m.buckets[key]
+	if !ok {
+		e = &entry{Bucket: Full(limit, now)}
+		m.buckets[key] = e
+	}
+	e.per = limit.Per
+
+	return e.Take(limit, now), nil
+}
+
+// sweep removes buckets idle long enough to be full again, a new bucket
+// behaves the same.
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, e := range m.buckets {
+		if now.Sub(e.Updated) >= e.per {
+			delete(m.buckets, key)
+		}
+	}
+}
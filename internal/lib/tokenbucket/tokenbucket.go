@@ -0,0 +1,129 @@
+// Package tokenbucket limits how often a client may act. A bucket holds up
+// to Limit.Requests tokens and gets them back evenly over Limit.Per, every
+// request takes one token. Buckets are kept in a Store, MemoryStore keeps
+// them in the process, other stores share them between replicas.
+package tokenbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit, must be REQUESTS/DURATION such as 60/1m, or off")
+
+// Limit allows Requests requests per Per. The zero Limit allows everything.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit parses limits such as 60/1m. An empty string and off disable limiting.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return Limit{}, nil
+	}
+
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w: %s", ErrInvalidLimit, s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("%w: %s", ErrInvalidLimit, s)
+	}
+
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("%w: %s", ErrInvalidLimit, s)
+	}
+
+	return Limit{Requests: n, Per: d}, nil
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0
+}
+
+// Rate returns how many tokens are given back per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// Result of taking a token.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token, zero for allowed requests.
+	RetryAfter time.Duration
+}
+
+// ResultOf returns the result of a request that left tokens in the bucket.
+func ResultOf(limit Limit, tokens float64, allowed bool) Result {
+	rate := limit.Rate()
+
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Store keeps buckets by key. TakeToken takes a token from the bucket of key,
+// new keys start with a full bucket.
+type Store interface {
+	TakeToken(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Bucket is the state of a key. Stores keep it and call Take.
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Full returns the bucket of a key seen for the first time.
+func Full(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Requests), Updated: now}
+}
+
+// Take gives back the tokens earned since the last request and takes one,
+// if there is one.
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	if elapsed := now.Sub(b.Updated); elapsed > 0 {
+		b.Tokens = min(float64(limit.Requests), b.Tokens+elapsed.Seconds()*limit.Rate())
+		b.Updated = now
+	}
+
+	if b.Tokens < 1 {
+		return ResultOf(limit, b.Tokens, false)
+	}
+
+	b.Tokens--
+
+	return ResultOf(limit, b.Tokens, true)
+}
@@ -0,0 +1,70 @@
+package tokenbucket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected Limit
+	}{
+		{s: "60/1m", expected: Limit{Requests: 60, Per: time.Minute}},
+		{s: " 5/1s ", expected: Limit{Requests: 5, Per: time.Second}},
+		{s: "off", expected: Limit{}},
+		{s: "", expected: Limit{}},
+	}
+	for _, tt := range tests {
+		if got, err := ParseLimit(tt.s); err != nil || got != tt.expected {
+			t.Errorf("ParseLimit(%q) = %v, %v, expected %v", tt.s, got, err, tt.expected)
+		}
+	}
+
+	for _, s := range []string{"60", "0/1m", "-1/1m", "60/", "60/0s", "sixty/1m"} {
+		if _, err := ParseLimit(s); !errors.Is(err, ErrInvalidLimit) {
+			t.Errorf("ParseLimit(%q) error = %v, expected %v", s, err, ErrInvalidLimit)
+		}
+	}
+}
+
+func TestMemoryStore_TakeToken(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Per: 3 * time.Second}
+
+	now := time.Now()
+	m := NewMemoryStore()
+	m.now = func() time.Time { return now }
+
+	for i := 2; i >= 0; i-- {
+		res, _ := m.TakeToken(ctx, "alice", limit)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("TakeToken() = %+v, expected allowed with %d remaining", res, i)
+		}
+	}
+
+	res, _ := m.TakeToken(ctx, "alice", limit)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Errorf("TakeToken() of an empty bucket = %+v, expected retry after 1s and reset in 3s", res)
+	}
+
+	if res, _ := m.TakeToken(ctx, "bob", limit); !res.Allowed {
+		t.Errorf("TakeToken() of another key = %+v, expected its own bucket", res)
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if res, _ := m.TakeToken(ctx, "alice", limit); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("TakeToken() after 1.5s = %+v, expected one token given back", res)
+	}
+
+	// idle buckets are full again and forgotten
+	now = now.Add(time.Hour)
+	if res, _ := m.TakeToken(ctx, "carol", limit); !res.Allowed {
+		t.Fatalf("TakeToken() = %+v, expected allowed", res)
+	}
+	if _, ok := m.buckets["alice"]; ok {
+		t.Error("TakeToken() kept the idle bucket")
+	}
+}
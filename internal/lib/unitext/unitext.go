@@ -0,0 +1,112 @@
+// Package unitext holds the Unicode rules for user supplied text: NFC
+// normalization, length in user-perceived characters (grapheme clusters),
+// rejection of control and invisible characters, and script allow-lists.
+package unitext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Validation tags added by Register.
+const (
+	TagMin     = "gmin"    // minimal length in grapheme clusters
+	TagMax     = "gmax"    // maximal length in grapheme clusters
+	TagVisible = "visible" // no control or invisible characters
+)
+
+// Normalize returns s in Unicode Normalization Form C, so that the same text
+// typed with precomposed or combining characters is stored the same way.
+func Normalize(s string) string {
+	return norm.NFC.String(s)
+}
+
+var lineEndings = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\t", " ")
+
+// NormalizeLines turns CRLF and CR line endings into LF and tabs into
+// spaces. They are control characters Visible rejects, but text pasted from
+// other systems is full of them.
+func NormalizeLines(s string) string {
+	return lineEndings.Replace(s)
+}
+
+// Len returns the number of grapheme clusters of s, "é" is one whether it
+// is one code point or two, and so is a flag emoji.
+func Len(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// Visible reports whether s is valid UTF-8 without control, format,
+// private use or unassigned characters. Line feeds are allowed in multiline
+// quotes, zero width (non-)joiners are needed by emoji and some scripts.
+func Visible(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\u200c' || r == '\u200d':
+		case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Co, unicode.Zl, unicode.Zp):
+			return false
+		case !unicode.In(r, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Zs):
+			return false
+		}
+	}
+
+	return true
+}
+
+// Scripts resolves Unicode script names such as "Latin" or "Cyrillic".
+func Scripts(names []string) ([]*unicode.RangeTable, error) {
+	var tables []*unicode.RangeTable
+
+	for _, name := range names {
+		table, ok := unicode.Scripts[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown unicode script %q", name)
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// InScripts reports whether every letter of s belongs to one of scripts.
+// Digits, punctuation, symbols and combining marks are shared by scripts
+// and always allowed, as is everything when scripts is empty.
+func InScripts(s string, scripts []*unicode.RangeTable) bool {
+	if len(scripts) == 0 {
+		return true
+	}
+
+	for _, r := range s {
+		if unicode.IsLetter(r) && !unicode.In(r, scripts...) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Register adds the gmin, gmax and visible tags to v.
+func Register(v *validator.Validate) {
+	v.RegisterValidation(TagMin, func(fl validator.FieldLevel) bool {
+		n, err := strconv.Atoi(fl.Param())
+		return err == nil && Len(fl.Field().String()) >= n
+	})
+	v.RegisterValidation(TagMax, func(fl validator.FieldLevel) bool {
+		n, err := strconv.Atoi(fl.Param())
+		return err == nil && Len(fl.Field().String()) <= n
+	})
+	v.RegisterValidation(TagVisible, func(fl validator.FieldLevel) bool {
+		return Visible(fl.Field().String())
+	})
+}
@@ -0,0 +1,92 @@
+package unitext
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestLen(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{s: "Конфуций", expected: 8},
+		{s: "Cafe\u0301", expected: 4},
+		{s: "🇷🇺", expected: 1},
+		{s: "👩\u200d💻 code", expected: 6},
+	}
+
+	for _, tt := range tests {
+		if got := Len(tt.s); got != tt.expected {
+			t.Errorf("Len(%q) = %d, expected %d", tt.s, got, tt.expected)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if Normalize("Cafe\u0301") != "Caf\u00e9" {
+		t.Errorf("Normalize() did not compose e and combining acute")
+	}
+}
+
+func TestNormalizeLines(t *testing.T) {
+	tests := map[string]string{
+		"one\r\ntwo\r\n": "one\ntwo\n",
+		"old\rmac":       "old\nmac",
+		"tab\there":      "tab here",
+		"line\nfeed":     "line\nfeed",
+	}
+	for s, expected := range tests {
+		if got := NormalizeLines(s); got != expected || !Visible(got) {
+			t.Errorf("NormalizeLines(%q) = %q, expected visible %q", s, got, expected)
+		}
+	}
+}
+
+func TestVisible(t *testing.T) {
+	visible := []string{
+		"Жизнь проста — но мы настаиваем на том, чтобы её усложнять.",
+		"Antoine de Saint-Exupéry",
+		"line one\nline two",
+		"👩\u200d💻",
+	}
+	for _, s := range visible {
+		if !Visible(s) {
+			t.Errorf("Visible(%q) = false, expected true", s)
+		}
+	}
+
+	invisible := []string{
+		"bell\a",
+		"zero\u200bwidth",
+		"right\u202eto left",
+		"private\ue000",
+		"broken \xff utf-8",
+	}
+	for _, s := range invisible {
+		if Visible(s) {
+			t.Errorf("Visible(%q) = true, expected false", s)
+		}
+	}
+}
+
+func TestInScripts(t *testing.T) {
+	scripts, err := Scripts([]string{"Latin", "Cyrillic"})
+	if err != nil {
+		t.Fatalf("Scripts() unexpected error = %v", err)
+	}
+
+	if !InScripts("Лев Толстой, Léon Tolstoï — 1828!", scripts) {
+		t.Errorf("InScripts() rejected Latin and Cyrillic text")
+	}
+	if InScripts("Ζήνων", scripts) {
+		t.Errorf("InScripts() accepted Greek text")
+	}
+	if !InScripts("Ζήνων", []*unicode.RangeTable{}) {
+		t.Errorf("InScripts() without scripts rejected text")
+	}
+
+	if _, err := Scripts([]string{"Klingon"}); err == nil {
+		t.Errorf("Scripts() accepted unknown script")
+	}
+}
@@ -0,0 +1,91 @@
+// Package validation describes invalid input field by field, so that clients
+// can point at the offending field instead of parsing a message.
+package validation
+
+import (
+	"app/internal/lib/unitext"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Codes of field errors. Validator tags without a code of their own keep
+// the tag name as the code.
+const (
+	CodeRequired   = "required"
+	CodeMin        = "min"
+	CodeMax        = "max"
+	CodePrintASCII = "printascii"
+	CodeVisible    = unitext.TagVisible
+	CodeScript     = "script"
+)
+
+// FieldError is one problem with one field. Field is the JSON name of the
+// field, elements of lists are addressed as tags[0].
+type FieldError struct {
+	Field   string            `json:"field"`
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Params  map[string]string `json:"params,omitempty"`
+}
+
+// Errors lists every problem found in the input.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// FromValidator converts validator.ValidationErrors in err. Field names are
+// prefixed with field, which names the value checked by validator.Var.
+// Errors other than validation ones are returned as nil.
+func FromValidator(err error, field string) Errors {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	errs := make(Errors, 0, len(verrs))
+	for _, fe := range verrs {
+		errs = append(errs, fieldError(field+fe.Field(), fe.Tag(), fe.Param(), fe.Kind()))
+	}
+
+	return errs
+}
+
+// fieldError describes a failed validator tag, gmin and gmax are reported
+// as min and max since clients do not care how characters are counted.
+// Lengths of lists are counted in items.
+func fieldError(field, tag, param string, kind reflect.Kind) FieldError {
+	unit := " characters long"
+	if kind == reflect.Slice {
+		unit = " items"
+	}
+
+	switch tag {
+	case CodeRequired:
+		return FieldError{Field: field, Code: CodeRequired, Message: "cannot be empty"}
+	case CodeMin, unitext.TagMin:
+		return FieldError{Field: field, Code: CodeMin, Message: "must be at least " + param + unit, Params: map[string]string{"min": param}}
+	case CodeMax, unitext.TagMax:
+		return FieldError{Field: field, Code: CodeMax, Message: "must be at most " + param + unit, Params: map[string]string{"max": param}}
+	case CodePrintASCII:
+		return FieldError{Field: field, Code: CodePrintASCII, Message: "must contain printable ASCII characters only"}
+	case CodeVisible:
+		return FieldError{Field: field, Code: CodeVisible, Message: "contains control or invisible characters"}
+	}
+
+	fe := FieldError{Field: field, Code: tag, Message: "is not valid"}
+	if param != "" {
+		fe.Params = map[string]string{tag: param}
+	}
+
+	return fe
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type quote struct {
+	Author string   `json:"author" validate:"required,min=3,printascii"`
+	Text   string   `json:"quote" validate:"required,max=5"`
+	Tags   []string `json:"tags" validate:"max=1,dive,min=2"`
+}
+
+func TestFromValidator(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	})
+
+	err := v.Struct(quote{Author: "Ян", Text: "Too long", Tags: []string{"a", "b"}})
+
+	expected := Errors{
+		{Field: "author", Code: CodeMin, Params: map[string]string{"min": "3"}},
+		{Field: "quote", Code: CodeMax, Params: map[string]string{"max": "5"}},
+		{Field: "tags", Code: CodeMax, Params: map[string]string{"max": "1"}},
+	}
+
+	errs := FromValidator(err, "")
+	if len(errs) != len(expected) {
+		t.Fatalf("FromValidator() = %v, expected %d errors", errs, len(expected))
+	}
+	for i, e := range expected {
+		got := errs[i]
+		if got.Field != e.Field || got.Code != e.Code || !reflect.DeepEqual(got.Params, e.Params) || got.Message == "" {
+			t.Errorf("FromValidator()[%d] = %+v, expected %+v with a message", i, got, e)
+		}
+	}
+
+	if !strings.Contains(errs[2].Message, "items") {
+		t.Errorf("FromValidator() message for a list = %q, expected a count of items", errs[2].Message)
+	}
+
+	tagErrs := FromValidator(v.Var([]string{"a"}, "dive,min=2"), "tags")
+	if len(tagErrs) != 1 || tagErrs[0].Field != "tags[0]" {
+		t.Errorf("FromValidator() of a list = %v, expected an error of tags[0]", tagErrs)
+	}
+
+	if FromValidator(nil, "") != nil {
+		t.Errorf("FromValidator(nil) is not nil")
+	}
+}
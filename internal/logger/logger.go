@@ -31,16 +31,16 @@ func New(level string) *slog.Logger {
 
 	switch level {
 	case LevelDebug:
-		log = slog.New(slog.NewJSONHandler(multiWriter, &slog.HandlerOptions{
+		log = slog.New(contextHandler{slog.NewJSONHandler(multiWriter, &slog.HandlerOptions{
 			Level:     slog.LevelDebug,
 			AddSource: true,
-		}))
+		})})
 
 	case LevelDev:
-		log = slog.New(slog.NewJSONHandler(multiWriter, &slog.HandlerOptions{
+		log = slog.New(contextHandler{slog.NewJSONHandler(multiWriter, &slog.HandlerOptions{
 			Level:     slog.LevelInfo,
 			AddSource: true,
-		}))
+		})})
 	}
 	return log
 }
@@ -0,0 +1,208 @@
+// Package apikeys issues and checks API keys. A key is shown once when it is
+// created, only its SHA-256 hash is stored. Every key belongs to a user and
+// acts with the role of that user.
+package apikeys
+
+import (
+	"app/internal/domain/access"
+	"app/internal/domain/errcode"
+	"app/internal/storage"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scopes a key can be granted.
+const (
+	ScopeRead   = "quotes:read"
+	ScopeWrite  = "quotes:write"
+	ScopeDelete = "quotes:delete"
+)
+
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeDelete}
+
+const (
+	keyPrefix    = "qk_"
+	secretBytes  = 32
+	prefixLength = len(keyPrefix) + 8 // shown in listings
+)
+
+var (
+	ErrCreateKeyFailed = fmt.Errorf("failed to create api key")
+	ErrListKeysFailed  = fmt.Errorf("failed to list api keys")
+	ErrRevokeKeyFailed = fmt.Errorf("failed to revoke api key")
+	ErrAuthFailed      = fmt.Errorf("failed to authenticate api key")
+
+	ErrInvalidName   = errcode.New(errcode.InvalidArgument, "invalid api key name, must not be empty")
+	ErrUnknownUser   = errcode.New(errcode.InvalidArgument, "unknown user, create the user first")
+	ErrInvalidScopes = errcode.New(errcode.InvalidArgument, "invalid api key scopes, must be quotes:read, quotes:write or quotes:delete")
+	ErrInvalidTTL    = errcode.New(errcode.InvalidArgument, "invalid api key lifetime, must not be negative")
+
+	ErrMissingKey = errcode.New(errcode.Unauthenticated, "API key is required")
+	ErrInvalidKey = errcode.New(errcode.Unauthenticated, "API key is not valid, expired or revoked")
+	ErrNoScope    = errcode.New(errcode.PermissionDenied, "API key does not have the scope required")
+)
+
+type Storage interface {
+	SaveAPIKey(ctx context.Context, key *storage.APIKey) (int, error)
+	APIKeyByHash(ctx context.Context, hash string) (*storage.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*storage.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	GetUser(ctx context.Context, id int) (*storage.User, error)
+	GetUserByName(ctx context.Context, name string) (*storage.User, error)
+}
+
+type Service struct {
+	storage Storage
+	log     *slog.Logger
+	now     func() time.Time
+}
+
+func New(log *slog.Logger, storage Storage) *Service {
+	return &Service{
+		storage: storage,
+		log:     log,
+		now:     time.Now,
+	}
+}
+
+// Create issues a key of the user with the scopes, ttl 0 creates a key that
+// never expires. The returned token is the key itself, it cannot be read later.
+func (s *Service) Create(ctx context.Context, user string, name string, scopes []string, ttl time.Duration) (string, *storage.APIKey, error) {
+	s.log.DebugContext(ctx, "Creating api key", "user", user, "name", name, "scopes", scopes, "ttl", ttl)
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		s.log.ErrorContext(ctx, ErrInvalidName.Error())
+		return "", nil, ErrInvalidName
+	}
+
+	if len(scopes) == 0 {
+		s.log.ErrorContext(ctx, ErrInvalidScopes.Error())
+		return "", nil, ErrInvalidScopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			s.log.ErrorContext(ctx, ErrInvalidScopes.Error(), "scope", scope)
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidScopes, scope)
+		}
+	}
+
+	if ttl < 0 {
+		s.log.ErrorContext(ctx, ErrInvalidTTL.Error(), "ttl", ttl)
+		return "", nil, ErrInvalidTTL
+	}
+
+	owner, err := s.storage.GetUserByName(ctx, strings.TrimSpace(user))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			s.log.ErrorContext(ctx, ErrUnknownUser.Error(), "user", user)
+			return "", nil, fmt.Errorf("%w: %s", ErrUnknownUser, user)
+		}
+		s.log.ErrorContext(ctx, ErrCreateKeyFailed.Error(), "error", err)
+		return "", nil, fmt.Errorf("%w: %w", ErrCreateKeyFailed, err)
+	}
+
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		s.log.ErrorContext(ctx, ErrCreateKeyFailed.Error(), "error", err)
+		return "", nil, fmt.Errorf("%w: %w", ErrCreateKeyFailed, err)
+	}
+	token := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &storage.APIKey{
+		UserID: owner.ID,
+		Name:   name,
+		Prefix: token[:prefixLength],
+		Hash:   Hash(token),
+		Scopes: slices.Compact(slices.Sorted(slices.Values(scopes))),
+	}
+	if ttl > 0 {
+		expires := s.now().Add(ttl)
+		key.ExpiresAt = &expires
+	}
+
+	id, err := s.storage.SaveAPIKey(ctx, key)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrCreateKeyFailed.Error(), "error", err)
+		return "", nil, fmt.Errorf("%w: %w", ErrCreateKeyFailed, err)
+	}
+	key.ID = id
+
+	s.log.InfoContext(ctx, "API key created", "id", id, "user", owner.Name, "name", name, "scopes", key.Scopes)
+
+	return token, key, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*storage.APIKey, error) {
+	keys, err := s.storage.ListAPIKeys(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrListKeysFailed.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrListKeysFailed, err)
+	}
+
+	return keys, nil
+}
+
+// Revoke disables the key at once, it returns storage.ErrAPIKeyNotFound for
+// unknown and already revoked keys.
+func (s *Service) Revoke(ctx context.Context, id int) error {
+	if err := s.storage.RevokeAPIKey(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			return err
+		}
+		s.log.ErrorContext(ctx, ErrRevokeKeyFailed.Error(), "error", err, "id", id)
+		return fmt.Errorf("%w: %w", ErrRevokeKeyFailed, err)
+	}
+
+	s.log.InfoContext(ctx, "API key revoked", "id", id)
+	return nil
+}
+
+// Authenticate returns the key of the token and the user it acts as.
+// Unknown, revoked and expired keys are rejected with ErrInvalidKey.
+func (s *Service) Authenticate(ctx context.Context, token string) (*storage.APIKey, *access.Principal, error) {
+	if token == "" {
+		return nil, nil, ErrMissingKey
+	}
+
+	key, err := s.storage.APIKeyByHash(ctx, Hash(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			return nil, nil, ErrInvalidKey
+		}
+		s.log.ErrorContext(ctx, ErrAuthFailed.Error(), "error", err)
+		return nil, nil, fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	}
+
+	if key.RevokedAt != nil || key.ExpiresAt != nil && !s.now().Before(*key.ExpiresAt) {
+		return nil, nil, fmt.Errorf("%w: key %d", ErrInvalidKey, key.ID)
+	}
+
+	user, err := s.storage.GetUser(ctx, key.UserID)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrAuthFailed.Error(), "error", err, "keyID", key.ID)
+		return nil, nil, fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	}
+
+	return key, &access.Principal{Subject: user.Name, Role: access.Role(user.Role)}, nil
+}
+
+// Allows reports whether the key was granted the scope.
+func Allows(key *storage.APIKey, scope string) bool {
+	return slices.Contains(key.Scopes, scope)
+}
+
+// Hash returns the stored form of a token.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,120 @@
+package apikeys
+
+import (
+	"app/internal/domain/access"
+	"app/internal/storage"
+	"app/internal/storage/memory"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newService(t *testing.T) *Service {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	m := memory.New(log)
+
+	if _, err := m.SaveUser(context.Background(), &storage.User{Name: "alice", Role: string(access.Contributor)}); err != nil {
+		t.Fatal(err)
+	}
+
+	return New(log, m)
+}
+
+func TestService_Create(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	token, key, err := s.Create(ctx, "alice", " deploy bot ", []string{ScopeWrite, ScopeRead, ScopeRead}, time.Hour)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if !strings.HasPrefix(token, keyPrefix) || !strings.HasPrefix(token, key.Prefix) {
+		t.Errorf("Create() token = %q, expected it to start with %q", token, key.Prefix)
+	}
+	if key.Name != "deploy bot" || strings.Join(key.Scopes, ",") != "quotes:read,quotes:write" || key.ExpiresAt == nil {
+		t.Errorf("Create() key = %+v, expected trimmed name, sorted unique scopes and expiry", key)
+	}
+	if key.UserID == 0 {
+		t.Error("Create() key does not belong to the user")
+	}
+	if key.Hash == token || key.Hash != Hash(token) {
+		t.Error("Create() stores the key instead of its hash")
+	}
+
+	invalid := []struct {
+		user   string
+		name   string
+		scopes []string
+		ttl    time.Duration
+		err    error
+	}{
+		{"alice", "", []string{ScopeRead}, 0, ErrInvalidName},
+		{"alice", "bot", nil, 0, ErrInvalidScopes},
+		{"alice", "bot", []string{"quotes:admin"}, 0, ErrInvalidScopes},
+		{"alice", "bot", []string{ScopeRead}, -time.Hour, ErrInvalidTTL},
+		{"bob", "bot", []string{ScopeRead}, 0, ErrUnknownUser},
+	}
+	for _, tt := range invalid {
+		if _, _, err := s.Create(ctx, tt.user, tt.name, tt.scopes, tt.ttl); !errors.Is(err, tt.err) {
+			t.Errorf("Create(%q, %q, %v, %s) error = %v, expected %v", tt.user, tt.name, tt.scopes, tt.ttl, err, tt.err)
+		}
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	s := newService(t)
+	ctx := context.Background()
+
+	token, created, err := s.Create(ctx, "alice", "reader", []string{ScopeRead}, time.Hour)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	key, caller, err := s.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if key.ID != created.ID || !Allows(key, ScopeRead) || Allows(key, ScopeDelete) {
+		t.Errorf("Authenticate() = %+v, expected key %d with read scope only", key, created.ID)
+	}
+	if caller.Subject != "alice" || caller.Role != access.Contributor {
+		t.Errorf("Authenticate() principal = %+v, expected contributor alice", caller)
+	}
+
+	if _, _, err := s.Authenticate(ctx, ""); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("Authenticate() without a key error = %v, expected %v", err, ErrMissingKey)
+	}
+	if _, _, err := s.Authenticate(ctx, token+"x"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate() of an unknown key error = %v, expected %v", err, ErrInvalidKey)
+	}
+
+	// expired
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, _, err := s.Authenticate(ctx, token); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate() of an expired key error = %v, expected %v", err, ErrInvalidKey)
+	}
+	s.now = time.Now
+
+	// revoked
+	if err := s.Revoke(ctx, created.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, _, err := s.Authenticate(ctx, token); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate() of a revoked key error = %v, expected %v", err, ErrInvalidKey)
+	}
+	if err := s.Revoke(ctx, created.ID); !errors.Is(err, storage.ErrAPIKeyNotFound) {
+		t.Errorf("Revoke() twice error = %v, expected %v", err, storage.ErrAPIKeyNotFound)
+	}
+
+	keys, err := s.List(ctx)
+	if err != nil || len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Errorf("List() = %v, %v, expected the revoked key", keys, err)
+	}
+}
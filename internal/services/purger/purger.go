@@ -0,0 +1,59 @@
+package purger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Purger interface {
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// Service periodically removes quotes that stayed in the trash longer than the retention period.
+type Service struct {
+	purger    Purger
+	retention time.Duration
+	interval  time.Duration
+	log       *slog.Logger
+}
+
+func New(log *slog.Logger, purger Purger, retention time.Duration, interval time.Duration) *Service {
+	return &Service{
+		purger:    purger,
+		retention: retention,
+		interval:  interval,
+		log:       log,
+	}
+}
+
+// Run purges the trash once and then on every interval tick until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	s.log.InfoContext(ctx, "Trash purger started", "retention", s.retention, "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.log.InfoContext(ctx, "Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) purge(ctx context.Context) {
+	purged, err := s.purger.Purge(ctx, s.retention)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to purge trash", "error", err)
+		return
+	}
+
+	if purged > 0 {
+		s.log.InfoContext(ctx, "Trash purged", "count", purged)
+	}
+}
@@ -0,0 +1,51 @@
+package quteos
+
+import (
+	"app/internal/domain/access"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrAuthorizeFailed = fmt.Errorf("failed to check quote access")
+
+// requireRole returns an error unless the caller has at least role.
+func (s *Service) requireRole(ctx context.Context, role access.Role) error {
+	if err := access.Require(ctx, role); err != nil {
+		s.log.InfoContext(ctx, "Access denied", "error", err)
+
+		return err
+	}
+
+	return nil
+}
+
+// requireOwner returns an error unless the caller may change the quote, see
+// access.RequireOwner. The owner is not looked up for callers who may change
+// every quote.
+func (s *Service) requireOwner(ctx context.Context, id int) error {
+	if p, ok := access.FromContext(ctx); !ok || p.Role.AtLeast(access.Editor) {
+		return nil
+	}
+
+	owner, err := s.storage.QuoteOwner(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrQuoteNotFound) {
+			s.log.ErrorContext(ctx, storage.ErrQuoteNotFound.Error(), "id", id)
+
+			return fmt.Errorf("%w: %d", storage.ErrQuoteNotFound, id)
+		}
+		s.log.ErrorContext(ctx, ErrAuthorizeFailed.Error(), "error", err, "id", id)
+
+		return fmt.Errorf("%w: %w", ErrAuthorizeFailed, err)
+	}
+
+	if err := access.RequireOwner(ctx, owner); err != nil {
+		s.log.InfoContext(ctx, "Access denied", "error", err, "id", id)
+
+		return err
+	}
+
+	return nil
+}
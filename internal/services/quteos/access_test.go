@@ -0,0 +1,114 @@
+package quteos
+
+import (
+	"app/internal/domain/access"
+	"app/internal/domain/models"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func as(subject string, role access.Role) context.Context {
+	return access.WithPrincipal(context.Background(), &access.Principal{Subject: subject, Role: role})
+}
+
+func TestService_Access(t *testing.T) {
+	s := newMemoryService(t, Settings{QuoteSimilarity: 1})
+
+	alice := as("alice", access.Contributor)
+	bob := as("bob", access.Contributor)
+
+	saved, err := s.Save(alice, &models.Quote{Text: "Life is simple", Author: "Confucius"}, false)
+	if err != nil {
+		t.Fatalf("Save() as a contributor error = %v", err)
+	}
+	id := strconv.Itoa(saved.ID)
+
+	q, err := s.Get(context.Background(), id)
+	if err != nil || q.Owner != "alice" {
+		t.Fatalf("Get() = %+v, %v, expected a quote owned by alice", q, err)
+	}
+
+	// a quote saved before ownership belongs to nobody
+	legacy, err := s.Save(context.Background(), &models.Quote{Text: "Know thyself", Author: "Socrates"}, false)
+	if err != nil {
+		t.Fatalf("Save() without a caller error = %v", err)
+	}
+	legacyID := strconv.Itoa(legacy.ID)
+
+	if _, err := s.Save(as("carol", access.Reader), &models.Quote{Text: "Real knowledge", Author: "Confucius"}, false); !errors.Is(err, access.ErrRoleRequired) {
+		t.Errorf("Save() as a reader error = %v, expected %v", err, access.ErrRoleRequired)
+	}
+
+	denied := []struct {
+		name string
+		ctx  context.Context
+		id   string
+		err  error
+	}{
+		{"other contributor", bob, id, access.ErrNotOwner},
+		{"quote without owner", alice, legacyID, access.ErrNotOwner},
+		{"reader", as("alice", access.Reader), id, access.ErrRoleRequired},
+	}
+	for _, tt := range denied {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.AddTags(tt.ctx, tt.id, []string{"wisdom"}); !errors.Is(err, tt.err) {
+				t.Errorf("AddTags() error = %v, expected %v", err, tt.err)
+			}
+			if err := s.Delete(tt.ctx, tt.id); !errors.Is(err, tt.err) {
+				t.Errorf("Delete() error = %v, expected %v", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := s.Update(alice, id, &models.Quote{Text: "Life is really simple", Author: "Confucius"}, q.Version); err != nil {
+		t.Errorf("Update() of an own quote error = %v", err)
+	}
+	if err := s.Delete(bob, strconv.Itoa(saved.ID+100)); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Delete() of a missing quote error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+	if err := s.Delete(as("dave", access.Editor), legacyID); err != nil {
+		t.Errorf("Delete() as an editor error = %v", err)
+	}
+	if err := s.Delete(alice, id); err != nil {
+		t.Errorf("Delete() of an own quote error = %v", err)
+	}
+
+	if _, err := s.Trash(as("carol", access.Reader)); !errors.Is(err, access.ErrRoleRequired) {
+		t.Errorf("Trash() as a reader error = %v, expected %v", err, access.ErrRoleRequired)
+	}
+	if trash, err := s.Trash(bob); err != nil || len(trash) != 0 {
+		t.Errorf("Trash() of another contributor = %v, %v, expected an empty list", trash, err)
+	}
+	if trash, err := s.Trash(alice); err != nil || len(trash) != 1 || trash[0].Owner != "alice" {
+		t.Errorf("Trash() of a contributor = %v, %v, expected only the own quote", trash, err)
+	}
+	if trash, err := s.Trash(as("dave", access.Editor)); err != nil || len(trash) != 2 {
+		t.Errorf("Trash() as an editor = %v, %v, expected every deleted quote", trash, err)
+	}
+
+	admin := as("erin", access.Admin)
+	pinned, err := s.Save(admin, &models.Quote{Text: "The unexamined life is not worth living", Author: "Socrates"}, false)
+	if err != nil {
+		t.Fatalf("Save() as an admin error = %v", err)
+	}
+	for _, ctx := range []context.Context{alice, as("dave", access.Editor)} {
+		if err := s.PinDaily(ctx, "2025-01-01", pinned.ID); !errors.Is(err, access.ErrRoleRequired) {
+			t.Errorf("PinDaily() below admin error = %v, expected %v", err, access.ErrRoleRequired)
+		}
+	}
+	if err := s.PinDaily(admin, "2025-01-01", pinned.ID); err != nil {
+		t.Errorf("PinDaily() as an admin error = %v", err)
+	}
+	if err := s.UnpinDaily(as("dave", access.Editor), "2025-01-01"); !errors.Is(err, access.ErrRoleRequired) {
+		t.Errorf("UnpinDaily() as an editor error = %v, expected %v", err, access.ErrRoleRequired)
+	}
+	if err := s.UnpinDaily(admin, "2025-01-01"); err != nil {
+		t.Errorf("UnpinDaily() as an admin error = %v", err)
+	}
+	if _, err := s.Purge(as("dave", access.Editor), 0); !errors.Is(err, access.ErrRoleRequired) {
+		t.Errorf("Purge() as an editor error = %v, expected %v", err, access.ErrRoleRequired)
+	}
+}
@@ -0,0 +1,122 @@
+package quteos
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/domain/models"
+	"app/internal/lib/cursor"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ErrGetAuthorFailed  = fmt.Errorf("failed to get author")
+	ErrInvalidAuthorID  = errcode.New(errcode.InvalidArgument, "invalid author ID, must be a positive integer")
+	ErrListAuthorFailed = fmt.Errorf("failed to list authors")
+)
+
+type AuthorsPage struct {
+	Authors    []*models.Author
+	NextCursor string // empty on the last page
+	Total      *int
+}
+
+// ListAuthors lists authors having at least one quote, ordered by id.
+func (s *Service) ListAuthors(ctx context.Context, req PageRequest) (*AuthorsPage, error) {
+	s.log.DebugContext(ctx, "Listing authors", "limit", req.Limit, "cursor", req.Cursor)
+
+	page, err := s.page(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := s.storage.ListAuthors(ctx, page)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrListAuthorFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrListAuthorFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Authors retrieved successfully", "count", len(authors.Authors))
+
+	result := &AuthorsPage{
+		Authors: authors.Authors,
+		Total:   authors.Total,
+	}
+
+	if result.Authors == nil {
+		result.Authors = []*models.Author{}
+	}
+
+	if authors.HasMore && len(authors.Authors) > 0 {
+		result.NextCursor = cursor.Encode(cursor.Cursor{
+			ID: authors.Authors[len(authors.Authors)-1].ID,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetAuthor(ctx context.Context, id string) (*models.Author, error) {
+	s.log.DebugContext(ctx, "Getting author", "id", id)
+
+	intID, err := validateAuthorID(id)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidAuthorID.Error(), "error", err)
+		return nil, err
+	}
+
+	author, err := s.storage.GetAuthor(ctx, intID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAuthorNotFound) {
+			s.log.ErrorContext(ctx, storage.ErrAuthorNotFound.Error(), "id", id)
+
+			return nil, fmt.Errorf("%w: %s", storage.ErrAuthorNotFound, id)
+		}
+		s.log.ErrorContext(ctx, ErrGetAuthorFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrGetAuthorFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Author retrieved successfully", "author", author)
+
+	return author, nil
+}
+
+// ListAuthorQuotes lists quotes of the author with the given id.
+// Unknown authors are reported as storage.ErrAuthorNotFound rather than an empty page.
+func (s *Service) ListAuthorQuotes(ctx context.Context, id string, req PageRequest) (*QuotesPage, error) {
+	author, err := s.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.DebugContext(ctx, "Listing quotes by author ID", "id", author.ID, "limit", req.Limit, "cursor", req.Cursor)
+
+	page, err := s.page(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.storage.ListByAuthorID(ctx, author.ID, page)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Quotes retrieved successfully", "count", len(quotes.Quotes))
+
+	return newQuotesPage(quotes), nil
+}
+
+func validateAuthorID(id string) (int, error) {
+	intID, err := strconv.Atoi(id)
+	if err != nil || intID <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAuthorID, id)
+	}
+
+	return intID, nil
+}
@@ -0,0 +1,229 @@
+package quteos
+
+import (
+	"app/internal/domain/access"
+	"app/internal/domain/errcode"
+	"app/internal/storage"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDailyQuoteFailed = fmt.Errorf("failed to get daily quote")
+	ErrPinDailyFailed   = fmt.Errorf("failed to pin daily quote")
+	ErrInvalidTimezone  = errcode.New(errcode.InvalidArgument, "invalid time zone, must be an IANA name such as Europe/Moscow")
+	ErrInvalidDate      = errcode.New(errcode.InvalidArgument, "invalid date, must be formatted as YYYY-MM-DD")
+)
+
+type DailyQuote struct {
+	Date   string                `json:"date"`
+	Pinned bool                  `json:"pinned"`
+	Quote  *storage.StorageQuote `json:"quote"`
+	// Expires is the next midnight in the requested time zone.
+	Expires time.Time `json:"-"`
+}
+
+// DailyQuote returns the quote of the current day in the time zone tz (UTC by
+// default). Every caller gets the same quote for the same date unless quotes
+// are added or removed during the day. A quote pinned for the date wins.
+// The pick is remembered per date, so quotes are counted once a day rather
+// than on every request.
+func (s *Service) DailyQuote(ctx context.Context, tz string) (*DailyQuote, error) {
+	s.log.DebugContext(ctx, "Getting daily quote", "tz", tz)
+
+	loc := time.UTC
+	if tz != "" {
+		var err error
+		// Local depends on the server and would break determinism
+		if loc, err = time.LoadLocation(tz); err != nil || tz == "Local" {
+			s.log.ErrorContext(ctx, ErrInvalidTimezone.Error(), "tz", tz)
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, tz)
+		}
+	}
+
+	now := time.Now().In(loc)
+	year, month, day := now.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	daily := &DailyQuote{
+		Date:    date.Format(time.DateOnly),
+		Expires: time.Date(year, month, day+1, 0, 0, 0, 0, loc),
+	}
+
+	pinned, err := s.storage.DailyPin(ctx, date)
+	switch {
+	case err == nil:
+		daily.Quote = pinned
+		daily.Pinned = true
+
+		s.log.DebugContext(ctx, "Pinned daily quote retrieved successfully", "date", daily.Date, "id", pinned.Id)
+		return daily, nil
+
+	case !errors.Is(err, storage.ErrPinNotFound):
+		s.log.ErrorContext(ctx, ErrDailyQuoteFailed.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrDailyQuoteFailed, err)
+	}
+
+	if id, ok := s.daily.get(date); ok {
+		quote, err := s.storage.Get(ctx, id)
+		switch {
+		case err == nil:
+			daily.Quote = quote
+
+			s.log.DebugContext(ctx, "Daily quote retrieved successfully", "date", daily.Date, "id", quote.Id)
+			return daily, nil
+
+		case !errors.Is(err, storage.ErrQuoteNotFound):
+			s.log.ErrorContext(ctx, ErrDailyQuoteFailed.Error(), "error", err)
+			return nil, fmt.Errorf("%w: %w", ErrDailyQuoteFailed, err)
+		}
+
+		// the quote was deleted during the day, pick another one
+		s.log.DebugContext(ctx, "Daily quote is deleted, picking again", "date", daily.Date, "id", id)
+	}
+
+	dayNumber := int(date.Unix() / int64(24*time.Hour/time.Second))
+
+	quote, err := s.storage.Pick(ctx, func(count int) int {
+		return dailyIndex(dayNumber, count)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrQuotesListEmpty) {
+			s.log.ErrorContext(ctx, storage.ErrQuotesListEmpty.Error())
+			return nil, err
+		}
+		s.log.ErrorContext(ctx, ErrDailyQuoteFailed.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrDailyQuoteFailed, err)
+	}
+	daily.Quote = quote
+	s.daily.set(date, quote.Id)
+
+	s.log.DebugContext(ctx, "Daily quote retrieved successfully", "date", daily.Date, "id", quote.Id)
+
+	return daily, nil
+}
+
+// dailyPicks remembers the quotes picked for recent dates.
+type dailyPicks struct {
+	mu  sync.Mutex
+	ids map[time.Time]int
+}
+
+func (d *dailyPicks) get(date time.Time) (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id, ok := d.ids[date]
+	return id, ok
+}
+
+// set remembers the pick for the date and forgets dates no time zone is at
+// any more, they are at most a day apart from each other.
+func (d *dailyPicks) set(date time.Time, id int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.ids == nil {
+		d.ids = make(map[time.Time]int)
+	}
+	for day := range d.ids {
+		if day.Before(date.AddDate(0, 0, -2)) {
+			delete(d.ids, day)
+		}
+	}
+
+	d.ids[date] = id
+}
+
+// PinDaily makes the quote the quote of the day for the date, YYYY-MM-DD.
+// Only admins pin and unpin.
+func (s *Service) PinDaily(ctx context.Context, date string, quoteID int) error {
+	s.log.DebugContext(ctx, "Pinning daily quote", "date", date, "id", quoteID)
+
+	if err := s.requireRole(ctx, access.Admin); err != nil {
+		return err
+	}
+
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidDate.Error(), "date", date)
+		return fmt.Errorf("%w: %s", ErrInvalidDate, date)
+	}
+
+	if quoteID <= 0 {
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "id", quoteID)
+		return fmt.Errorf("%w: %d", ErrInvalidQuoteID, quoteID)
+	}
+
+	if err := s.storage.PinDaily(ctx, day, quoteID); err != nil {
+		if errors.Is(err, storage.ErrQuoteNotFound) {
+			s.log.ErrorContext(ctx, storage.ErrQuoteNotFound.Error(), "id", quoteID)
+			return fmt.Errorf("%w: %d", storage.ErrQuoteNotFound, quoteID)
+		}
+		s.log.ErrorContext(ctx, ErrPinDailyFailed.Error(), "error", err)
+		return fmt.Errorf("%w: %w", ErrPinDailyFailed, err)
+	}
+
+	return nil
+}
+
+func (s *Service) UnpinDaily(ctx context.Context, date string) error {
+	s.log.DebugContext(ctx, "Unpinning daily quote", "date", date)
+
+	if err := s.requireRole(ctx, access.Admin); err != nil {
+		return err
+	}
+
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidDate.Error(), "date", date)
+		return fmt.Errorf("%w: %s", ErrInvalidDate, date)
+	}
+
+	if err := s.storage.UnpinDaily(ctx, day); err != nil {
+		if errors.Is(err, storage.ErrPinNotFound) {
+			s.log.ErrorContext(ctx, storage.ErrPinNotFound.Error(), "date", date)
+			return fmt.Errorf("%w: %s", storage.ErrPinNotFound, date)
+		}
+		s.log.ErrorContext(ctx, ErrPinDailyFailed.Error(), "error", err)
+		return fmt.Errorf("%w: %w", ErrPinDailyFailed, err)
+	}
+
+	return nil
+}
+
+// dailyIndex maps a day number to a position in a pool of n quotes. Days are
+// split into cycles of n days and each cycle walks a different affine
+// permutation of the pool, so no position repeats until the pool is exhausted.
+func dailyIndex(day, n int) int {
+	if n == 1 {
+		return 0
+	}
+
+	cycle, pos := day/n, day%n
+
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, int64(cycle))
+	sum := h.Sum64()
+
+	// a*pos+b is a permutation of [0, n) when a is coprime with n
+	a := int(sum%uint64(n-1)) + 1
+	for gcd(a, n) != 1 {
+		a++
+	}
+	b := int((sum >> 32) % uint64(n))
+
+	return (a*pos + b) % n
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,58 @@
+package quteos
+
+import (
+	"app/internal/storage"
+	"context"
+	"fmt"
+)
+
+var ErrExportFailed = fmt.Errorf("failed to export quotes")
+
+// ExportFilter takes the same filters as the list endpoint. Author and Tags
+// can't be combined, empty filter exports every quote.
+type ExportFilter struct {
+	Author      string
+	AuthorMatch string
+	Tags        []string
+	TagMatch    string
+}
+
+// Export calls fn for every quote matching the filter in id order. Filter
+// errors are returned before fn is called for the first time.
+func (s *Service) Export(ctx context.Context, filter ExportFilter, fn func(q *storage.StorageQuote) error) error {
+	s.log.DebugContext(ctx, "Exporting quotes", "filter", filter)
+
+	var exportFilter storage.ExportFilter
+
+	switch {
+	case filter.Author != "":
+		author, err := s.authorFilter(ctx, NormalizeAuthor(filter.Author), filter.AuthorMatch)
+		if err != nil {
+			return err
+		}
+		exportFilter.Author = &author
+
+	case len(filter.Tags) > 0:
+		tags, err := s.tagFilter(ctx, NormalizeTags(filter.Tags), filter.TagMatch)
+		if err != nil {
+			return err
+		}
+		exportFilter.Tags = &tags
+	}
+
+	exported := 0
+
+	err := s.storage.Export(ctx, exportFilter, func(q *storage.StorageQuote) error {
+		exported++
+		return fn(q)
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrExportFailed.Error(), "error", err, "exported", exported)
+
+		return fmt.Errorf("%w: %w", ErrExportFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Quotes exported successfully", "count", exported)
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package quteos
+
+import (
+	"app/internal/domain/access"
+	"app/internal/domain/errcode"
+	"app/internal/domain/models"
+	"app/internal/lib/fingerprint"
+	"app/internal/lib/quoteio"
+	"app/internal/lib/validation"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrImportFailed      = fmt.Errorf("failed to import quotes")
+	ErrImportRejected    = errcode.New(errcode.ImportRejected, "import rejected, some rows are not valid")
+	ErrInvalidImportMode = errcode.New(errcode.InvalidArgument, "invalid import mode, must be atomic or best_effort")
+)
+
+// Import modes. Atomic imports store every row or none of them, best effort
+// imports store every valid row.
+const (
+	ImportAtomic     = "atomic"
+	ImportBestEffort = "best_effort"
+
+	MaxImportRows = 10000
+)
+
+// ImportRowResult is the outcome of one row. Invalid rows list their
+// problems field by field in Errors, the same way other endpoints do.
+type ImportRowResult struct {
+	Row    int               `json:"row"`
+	ID     int               `json:"id,omitempty"`
+	Error  string            `json:"error,omitempty"`
+	Errors validation.Errors `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// Import validates rows with the same rules as Save and stores the valid ones.
+// Rows repeating a stored quote or an earlier row are reported as duplicates.
+// The report is returned together with ErrImportRejected when an atomic
+// import has invalid rows, the error carries the report for clients as well.
+func (s *Service) Import(ctx context.Context, rows []quoteio.Row, mode string) (*ImportReport, error) {
+	s.log.DebugContext(ctx, "Importing quotes", "rows", len(rows), "mode", mode)
+
+	if err := s.requireRole(ctx, access.Contributor); err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case "":
+		mode = ImportAtomic
+	case ImportAtomic, ImportBestEffort:
+	default:
+		s.log.ErrorContext(ctx, ErrInvalidImportMode.Error(), "mode", mode)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImportMode, mode)
+	}
+
+	report := &ImportReport{Rows: make([]ImportRowResult, len(rows))}
+
+	var (
+		valid     []*models.Quote
+		validRows []int // report indexes of valid quotes
+	)
+
+	for i, row := range rows {
+		report.Rows[i].Row = row.Number
+
+		if row.Err != nil {
+			report.Rows[i].Error = row.Err.Error()
+			continue
+		}
+
+		q := row.Quote
+		q.Author = NormalizeAuthor(q.Author)
+		q.Tags = NormalizeTags(q.Tags)
+
+		if err := s.validateQuote(ctx, &q); err != nil {
+			report.Rows[i].Error = ErrValidateQuote.Error()
+			errors.As(err, &report.Rows[i].Errors)
+			continue
+		}
+
+		valid = append(valid, &q)
+		validRows = append(validRows, i)
+	}
+
+	valid, validRows, err := s.dropDuplicates(ctx, valid, validRows, report)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Failed = len(rows) - len(valid)
+
+	if mode == ImportAtomic && report.Failed > 0 {
+		s.log.ErrorContext(ctx, ErrImportRejected.Error(), "failed", report.Failed)
+		return report, errcode.WithDetails(ErrImportRejected, report)
+	}
+
+	if len(valid) == 0 {
+		return report, nil
+	}
+
+	// quotes saved by others since dropDuplicates are reported like the
+	// duplicates it found
+	ids, err := s.storage.Import(ctx, valid, access.Owner(ctx), mode == ImportAtomic)
+	var duplicates *storage.ImportDuplicatesError
+	if errors.As(err, &duplicates) {
+		for i, dup := range duplicates.Duplicates {
+			report.Rows[validRows[i]].Error = (&DuplicateQuoteError{ExistingID: dup.ExistingID}).Error()
+			report.Failed++
+		}
+
+		if mode == ImportAtomic {
+			s.log.ErrorContext(ctx, ErrImportRejected.Error(), "failed", report.Failed)
+			return report, errcode.WithDetails(ErrImportRejected, report)
+		}
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrImportFailed.Error(), "error", err)
+
+		// best effort imports report failed batches per row
+		if ids == nil {
+			return nil, fmt.Errorf("%w: %w", ErrImportFailed, err)
+		}
+	}
+
+	for i, id := range ids {
+		row := &report.Rows[validRows[i]]
+
+		if duplicates != nil && duplicates.Duplicates[i] != nil {
+			continue
+		}
+		if id == 0 {
+			row.Error = ErrImportFailed.Error()
+			report.Failed++
+			continue
+		}
+
+		row.ID = id
+		report.Created++
+	}
+
+	s.log.DebugContext(ctx, "Quotes imported", "created", report.Created, "failed", report.Failed)
+
+	return report, nil
+}
+
+// dropDuplicates reports quotes that are already stored or repeat an earlier
+// quote of the import and returns the remaining ones.
+func (s *Service) dropDuplicates(ctx context.Context, quotes []*models.Quote, rows []int, report *ImportReport) ([]*models.Quote, []int, error) {
+	if len(quotes) == 0 {
+		return quotes, rows, nil
+	}
+
+	fps := make([]string, len(quotes))
+	for i, q := range quotes {
+		fps[i] = fingerprint.Of(q.Text, q.Author)
+	}
+
+	existing, err := s.storage.FindByFingerprints(ctx, fps)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrImportFailed.Error(), "error", err)
+		return nil, nil, fmt.Errorf("%w: %w", ErrImportFailed, err)
+	}
+
+	var (
+		unique     []*models.Quote
+		uniqueRows []int
+	)
+
+	seen := make(map[string]int) // row numbers by fingerprint
+	for i, q := range quotes {
+		row := &report.Rows[rows[i]]
+
+		if id, ok := existing[fps[i]]; ok {
+			row.Error = (&DuplicateQuoteError{ExistingID: id}).Error()
+			continue
+		}
+		if number, ok := seen[fps[i]]; ok {
+			row.Error = fmt.Sprintf("%s: repeats row %d", storage.ErrDuplicateQuote, number)
+			continue
+		}
+		seen[fps[i]] = row.Row
+
+		unique = append(unique, q)
+		uniqueRows = append(uniqueRows, rows[i])
+	}
+
+	return unique, uniqueRows, nil
+}
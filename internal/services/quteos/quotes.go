@@ -1,199 +1,630 @@
 package quteos
 
 import (
+	"app/internal/domain/access"
+	"app/internal/domain/errcode"
 	"app/internal/domain/models"
+	"app/internal/lib/cursor"
+	"app/internal/lib/unitext"
+	"app/internal/lib/validation"
 	"app/internal/storage"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-playground/validator/v10"
 )
 
 var (
-	ErrQuoteIsNil    = fmt.Errorf("quote is nil")
-	ErrValidateQuote = fmt.Errorf("validation failed for quote")
-
-	ErrSaveQuoteFailed   = fmt.Errorf("failed to save quote")
-	ErrDeleteQuoteFailed = fmt.Errorf("failed to delete quote")
-	ErrGetQuoteFailed    = fmt.Errorf("failed to get quote")
-	ErrInvalidQuoteID    = fmt.Errorf("invalid quote ID, must be a positive integer")
-	ErrInvalidAuthorName = fmt.Errorf("invalid author name, must be at least 2 characters long")
+	ErrQuoteIsNil    = errcode.New(errcode.InvalidBody, "quote is nil")
+	ErrValidateQuote = errcode.New(errcode.ValidationFailed, "validation failed for quote")
+
+	ErrSaveQuoteFailed    = fmt.Errorf("failed to save quote")
+	ErrDeleteQuoteFailed  = fmt.Errorf("failed to delete quote")
+	ErrUpdateQuoteFailed  = fmt.Errorf("failed to update quote")
+	ErrRestoreQuoteFailed = fmt.Errorf("failed to restore quote")
+	ErrPurgeQuotesFailed  = fmt.Errorf("failed to purge deleted quotes")
+	ErrGetQuoteFailed     = fmt.Errorf("failed to get quote")
+	ErrInvalidQuoteID     = errcode.New(errcode.InvalidArgument, "invalid quote ID, must be a positive integer")
+	ErrInvalidAuthorName  = errcode.New(errcode.InvalidArgument, "invalid author name, must be between 2 and 100 characters long")
+	ErrInvalidAuthorMatch = errcode.New(errcode.InvalidArgument, "invalid author match mode, must be exact, icase, prefix or fuzzy")
+	ErrInvalidCursor      = errcode.New(errcode.InvalidArgument, "invalid page cursor")
+	ErrInvalidPageLimit   = errcode.New(errcode.InvalidArgument, "invalid page limit, must be a positive integer")
+	ErrInvalidMaxLength   = errcode.New(errcode.InvalidArgument, "invalid max length, must be a positive integer")
+
+	ErrSearchFailed          = fmt.Errorf("failed to search quotes")
+	ErrInvalidSearchQuery    = errcode.New(errcode.InvalidArgument, "invalid search query, must be between 1 and 200 characters long")
+	ErrUnsupportedSearchLang = errcode.New(errcode.InvalidArgument, "unsupported search language, must be en or ru")
 )
 
+// DuplicateQuoteError is returned by Save, Update and Restore when the same
+// quote, ignoring case, spacing and punctuation, is already stored.
+type DuplicateQuoteError struct {
+	ExistingID int
+}
+
+func (e *DuplicateQuoteError) Error() string {
+	return fmt.Sprintf("quote already exists with id %d", e.ExistingID)
+}
+
+func (e *DuplicateQuoteError) Unwrap() error {
+	return storage.ErrDuplicateQuote
+}
+
+func (e *DuplicateQuoteError) Details() any {
+	return map[string]int{"id": e.ExistingID}
+}
+
+// asDuplicate converts a storage duplicate error to *DuplicateQuoteError.
+func asDuplicate(err error) (*DuplicateQuoteError, bool) {
+	var dup *storage.DuplicateError
+	if !errors.As(err, &dup) {
+		return nil, false
+	}
+
+	return &DuplicateQuoteError{ExistingID: dup.ExistingID}, true
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageRequest is a page of a quotes list as requested by a client.
+// Zero Limit means DefaultPageSize, empty Cursor means the first page.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	WithTotal bool
+}
+
+type QuotesPage struct {
+	Quotes     []*storage.StorageQuote
+	NextCursor string // empty on the last page
+	Total      *int
+}
+
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	unitext.Register(validate)
+
+	// field errors name fields the way clients send them
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	})
+}
+
+// Settings tune the service behaviour, see config.Config for defaults.
+type Settings struct {
+	// AuthorSimilarity is the minimal trigram similarity for fuzzy author matching.
+	AuthorSimilarity float64
+	// QuoteSimilarity is the trigram similarity from which a new quote is
+	// reported as a near duplicate of a stored one.
+	QuoteSimilarity float64
+	// AllowedScripts are Unicode script names letters of quotes and authors
+	// must be written in, empty allows every script. Names are checked by
+	// config.MustRead.
+	AllowedScripts []string
 }
 
 type Service struct {
-	storage storage.Storage
-	log     *slog.Logger
+	storage  storage.Storage
+	log      *slog.Logger
+	settings Settings
+	scripts  []*unicode.RangeTable // resolved settings.AllowedScripts
+	daily    dailyPicks
 }
 
-func New(storage *storage.Storage, log *slog.Logger) *Service {
+func New(storage *storage.Storage, log *slog.Logger, settings Settings) *Service {
+	scripts, err := unitext.Scripts(settings.AllowedScripts)
+	if err != nil {
+		log.Error("Unknown allowed script, every script is allowed", "error", err)
+	}
+
 	return &Service{
-		storage: *storage,
-		log:     log,
+		storage:  *storage,
+		log:      log,
+		settings: settings,
+		scripts:  scripts,
 	}
 }
 
-func (s *Service) Save(ctx context.Context, q *models.Quote) (int, error) {
-	s.log.Debug("Saving quote", "quote", q)
+// SaveResult is the id of a saved quote with the stored quotes it is similar to.
+type SaveResult struct {
+	ID      int
+	Similar []*storage.SimilarQuote
+}
+
+// Save stores the quote. Quotes similar to stored ones are saved with a
+// warning in SaveResult.Similar, or rejected with *SimilarQuoteError if strict.
+func (s *Service) Save(ctx context.Context, q *models.Quote, strict bool) (*SaveResult, error) {
+	s.log.DebugContext(ctx, "Saving quote", "quote", q, "strict", strict)
+
+	if err := s.requireRole(ctx, access.Contributor); err != nil {
+		return nil, err
+	}
 
 	if q == nil {
-		s.log.Error(ErrQuoteIsNil.Error())
+		s.log.ErrorContext(ctx, ErrQuoteIsNil.Error())
 
-		return 0, ErrQuoteIsNil
+		return nil, ErrQuoteIsNil
 	}
 
+	q.Author = NormalizeAuthor(q.Author)
+	q.Tags = NormalizeTags(q.Tags)
+
 	// validate quote
-	if err := validate.Struct(q); err != nil {
-		var errMsg strings.Builder
-		for _, err := range err.(validator.ValidationErrors) {
-			switch err.ActualTag() {
-			case "required":
-				errMsg.WriteString(err.Field() + " cannot be empty; ")
-			case "max":
-				errMsg.WriteString(err.Field() + " length exceeds " + err.Param() + " characters; ")
-			case "printascii":
-				errMsg.WriteString(err.Field() + " contains invalid characters; ")
-			case "min":
-				errMsg.WriteString(err.Field() + " must be at least " + err.Param() + " characters long; ")
-			}
-		}
+	if err := s.validateQuote(ctx, q); err != nil {
+		return nil, err
+	}
+
+	similar, err := s.nearDuplicates(ctx, q)
+	if err != nil {
+		return nil, err
+	}
 
-		s.log.Error(ErrValidateQuote.Error(), "error", errMsg.String())
+	if strict && len(similar) > 0 {
+		s.log.InfoContext(ctx, "Quote is similar to stored ones", "similar", len(similar))
 
-		return 0, fmt.Errorf("%w:%s", ErrValidateQuote, errMsg.String())
+		return nil, &SimilarQuoteError{Similar: similar}
 	}
 
 	// save quote to storage
 
-	id, err := s.storage.Save(ctx, q.Text, q.Author)
+	id, err := s.storage.Save(ctx, q.Text, q.Author, q.Tags, access.Owner(ctx))
 	if err != nil {
-		s.log.Error(ErrSaveQuoteFailed.Error(), "error", err)
+		if dup, ok := asDuplicate(err); ok {
+			s.log.InfoContext(ctx, "Quote already exists", "existingID", dup.ExistingID)
+
+			return nil, dup
+		}
+		s.log.ErrorContext(ctx, ErrSaveQuoteFailed.Error(), "error", err)
 
-		return 0, fmt.Errorf("%w: %w", ErrSaveQuoteFailed, err)
+		return nil, fmt.Errorf("%w: %w", ErrSaveQuoteFailed, err)
 	}
 
-	s.log.Debug("Quote saved successfully", "quote", q)
+	s.log.DebugContext(ctx, "Quote saved successfully", "quote", q)
 
-	return id, nil
+	return &SaveResult{ID: id, Similar: similar}, nil
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
-	s.log.Debug("Deleting quote", "id", id)
+	s.log.DebugContext(ctx, "Deleting quote", "id", id)
 
 	intID, err := validateQuoteID(id)
 	if err != nil {
-		s.log.Error(ErrInvalidQuoteID.Error(), "error", err)
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "error", err)
 		return fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
 	}
 
+	if err := s.requireOwner(ctx, intID); err != nil {
+		return err
+	}
+
 	if err := s.storage.Delete(ctx, intID); err != nil {
 		if errors.Is(err, storage.ErrQuoteNotFound) {
-			s.log.Error(ErrDeleteQuoteFailed.Error(), "err", storage.ErrQuoteNotFound, "id", id)
+			s.log.ErrorContext(ctx, ErrDeleteQuoteFailed.Error(), "err", storage.ErrQuoteNotFound, "id", id)
 
 			return fmt.Errorf("%w: %w", ErrDeleteQuoteFailed, storage.ErrQuoteNotFound)
 		}
-		s.log.Error(ErrDeleteQuoteFailed.Error(), "error", err)
+		s.log.ErrorContext(ctx, ErrDeleteQuoteFailed.Error(), "error", err)
 
 		return fmt.Errorf("%w: %w", ErrDeleteQuoteFailed, err)
 	}
 
-	s.log.Debug("Quote deleted successfully", "id", id)
+	s.log.DebugContext(ctx, "Quote deleted successfully", "id", id)
 
 	return nil
 }
 
-func (s *Service) RandomQuote(ctx context.Context) (*models.Quote, error) {
-	s.log.Debug("Getting random quote")
+// Update replaces the quote with the given id. The write only succeeds
+// if version matches the stored one, otherwise storage.ErrVersionConflict is returned.
+func (s *Service) Update(ctx context.Context, id string, q *models.Quote, version int) (*storage.StorageQuote, error) {
+	s.log.DebugContext(ctx, "Updating quote", "id", id, "version", version)
+
+	intID, err := validateQuoteID(id)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
+	}
+
+	if err := s.requireOwner(ctx, intID); err != nil {
+		return nil, err
+	}
+
+	if q == nil {
+		s.log.ErrorContext(ctx, ErrQuoteIsNil.Error())
+
+		return nil, ErrQuoteIsNil
+	}
+
+	q.Author = NormalizeAuthor(q.Author)
+
+	if err := s.validateQuote(ctx, q); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.storage.Update(ctx, intID, q.Text, q.Author, version)
+	if err != nil {
+		if dup, ok := asDuplicate(err); ok {
+			s.log.InfoContext(ctx, "Quote already exists", "existingID", dup.ExistingID, "id", id)
+
+			return nil, dup
+		}
+		s.log.ErrorContext(ctx, ErrUpdateQuoteFailed.Error(), "error", err, "id", id)
+
+		return nil, fmt.Errorf("%w: %w", ErrUpdateQuoteFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Quote updated successfully", "quote", updated)
+
+	return updated, nil
+}
+
+// RandomQuote returns a random quote matching the filter. Author and tag are
+// normalized the same way they are on save.
+func (s *Service) RandomQuote(ctx context.Context, filter storage.RandomFilter) (*models.Quote, error) {
+	filter.Author = NormalizeAuthor(filter.Author)
+	if tags := NormalizeTags([]string{filter.Tag}); len(tags) > 0 {
+		filter.Tag = tags[0]
+	}
+
+	s.log.DebugContext(ctx, "Getting random quote", "filter", filter)
 
-	quote, err := s.storage.Random(ctx)
+	if filter.MaxLength < 0 {
+		s.log.ErrorContext(ctx, ErrInvalidMaxLength.Error(), "max_length", filter.MaxLength)
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMaxLength, filter.MaxLength)
+	}
+
+	quote, err := s.storage.Random(ctx, filter)
 	if err != nil {
 		if errors.Is(err, storage.ErrQuoteNotFound) {
-			s.log.Error(storage.ErrQuoteNotFound.Error())
+			s.log.ErrorContext(ctx, storage.ErrQuoteNotFound.Error())
 
 			return nil, fmt.Errorf("%w: %w", storage.ErrQuoteNotFound, err)
 		}
-		s.log.Error(ErrGetQuoteFailed.Error(), "error", err)
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
 
 		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
 	}
 
-	s.log.Debug("Random quote retrieved successfully", "quote", quote)
+	s.log.DebugContext(ctx, "Random quote retrieved successfully", "quote", quote)
 
 	return quote, nil
 }
 
-func (s *Service) Get(ctx context.Context, id string) (*models.Quote, error) {
-	s.log.Debug("Getting quote", "id", id)
+func (s *Service) Get(ctx context.Context, id string) (*storage.StorageQuote, error) {
+	s.log.DebugContext(ctx, "Getting quote", "id", id)
 
 	intID, err := validateQuoteID(id)
 	if err != nil {
-		s.log.Error(ErrInvalidQuoteID.Error(), "error", err)
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "error", err)
 		return nil, fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
 	}
 
 	quote, err := s.storage.Get(ctx, intID)
 	if err != nil {
 		if errors.Is(err, storage.ErrQuoteNotFound) {
-			s.log.Error(storage.ErrQuoteNotFound.Error(), "id", id)
+			s.log.ErrorContext(ctx, storage.ErrQuoteNotFound.Error(), "id", id)
 
 			return nil, fmt.Errorf("%w: %s", storage.ErrQuoteNotFound, id)
 		}
-		s.log.Error(ErrGetQuoteFailed.Error(), "error", err)
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
 
 		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
 	}
 
-	s.log.Debug("Quote retrieved successfully", "quote", quote)
+	s.log.DebugContext(ctx, "Quote retrieved successfully", "quote", quote)
 
 	return quote, nil
 }
 
-func (s *Service) List(ctx context.Context) ([]*storage.StorageQuote, error) {
-	s.log.Debug("Listing all quotes")
+func (s *Service) List(ctx context.Context, req PageRequest) (*QuotesPage, error) {
+	s.log.DebugContext(ctx, "Listing all quotes", "limit", req.Limit, "cursor", req.Cursor)
 
-	quotes, err := s.storage.List(ctx)
+	page, err := s.page(ctx, req)
 	if err != nil {
-		s.log.Error(ErrGetQuoteFailed.Error(), "error", err)
+		return nil, err
+	}
+
+	quotes, err := s.storage.List(ctx, page)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
 
 		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
 	}
 
-	s.log.Debug("Quotes retrieved successfully", "count", len(quotes))
+	s.log.DebugContext(ctx, "Quotes retrieved successfully", "count", len(quotes.Quotes))
 
-	return quotes, nil
+	return newQuotesPage(quotes), nil
 }
 
-func (s *Service) ListByAuthor(ctx context.Context, author string) ([]*storage.StorageQuote, error) {
-	s.log.Debug("Listing all quotes")
+// ListByAuthor lists quotes of authors matching the name. match is one of
+// storage.MatchExact (the default), MatchICase, MatchPrefix or MatchFuzzy.
+func (s *Service) ListByAuthor(ctx context.Context, author string, match string, req PageRequest) (*QuotesPage, error) {
+	author = NormalizeAuthor(author)
 
-	quotes, err := s.storage.ListByAuthor(ctx, author)
+	s.log.DebugContext(ctx, "Listing quotes by author", "author", author, "match", match, "limit", req.Limit, "cursor", req.Cursor)
+
+	filter, err := s.authorFilter(ctx, author, match)
 	if err != nil {
-		s.log.Error(ErrGetQuoteFailed.Error(), "error", err)
+		return nil, err
+	}
+
+	page, err := s.page(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.storage.ListByAuthor(ctx, filter, page)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
 
 		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
 	}
 
-	s.log.Debug("Quotes retrieved successfully", "count", len(quotes))
+	s.log.DebugContext(ctx, "Quotes retrieved successfully", "count", len(quotes.Quotes))
+
+	return newQuotesPage(quotes), nil
+}
+
+// Author filters are measured in characters, like authors of quotes.
+const (
+	minAuthorFilter = 2
+	maxAuthorFilter = 100
+)
+
+// authorFilter checks the author and the match mode, empty means storage.MatchExact.
+func (s *Service) authorFilter(ctx context.Context, author string, match string) (storage.AuthorFilter, error) {
+	if n := unitext.Len(author); n < minAuthorFilter || n > maxAuthorFilter {
+		s.log.ErrorContext(ctx, ErrInvalidAuthorName.Error(), "author", author)
+		return storage.AuthorFilter{}, fmt.Errorf("%w: %s", ErrInvalidAuthorName, author)
+	}
+
+	switch match {
+	case "":
+		match = storage.MatchExact
+	case storage.MatchExact, storage.MatchICase, storage.MatchPrefix, storage.MatchFuzzy:
+	default:
+		s.log.ErrorContext(ctx, ErrInvalidAuthorMatch.Error(), "match", match)
+		return storage.AuthorFilter{}, fmt.Errorf("%w: %s", ErrInvalidAuthorMatch, match)
+	}
+
+	return storage.AuthorFilter{
+		Name:      author,
+		Match:     match,
+		Threshold: s.settings.AuthorSimilarity,
+	}, nil
+}
+
+// Search runs a full-text search over quote texts. lang selects the text
+// search configuration and defaults to english.
+func (s *Service) Search(ctx context.Context, query string, lang string, limit int) ([]*storage.SearchResult, error) {
+	s.log.DebugContext(ctx, "Searching quotes", "query", query, "lang", lang)
+
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > 200 {
+		s.log.ErrorContext(ctx, ErrInvalidSearchQuery.Error(), "query", query)
+		return nil, ErrInvalidSearchQuery
+	}
+
+	switch lang {
+	case "":
+		lang = storage.SearchEnglish
+	case storage.SearchEnglish, storage.SearchRussian:
+	default:
+		s.log.ErrorContext(ctx, ErrUnsupportedSearchLang.Error(), "lang", lang)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSearchLang, lang)
+	}
+
+	page, err := s.page(ctx, PageRequest{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.storage.Search(ctx, query, lang, page.Limit)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrSearchFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrSearchFailed, err)
+	}
+
+	if results == nil {
+		results = []*storage.SearchResult{}
+	}
+
+	s.log.DebugContext(ctx, "Quotes found", "count", len(results))
+
+	return results, nil
+}
+
+// Trash lists quotes that were deleted but not purged yet. Contributors
+// only see their own quotes, the ones they may restore.
+func (s *Service) Trash(ctx context.Context) ([]*storage.StorageQuote, error) {
+	s.log.DebugContext(ctx, "Listing deleted quotes")
+
+	if err := s.requireRole(ctx, access.Contributor); err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.storage.ListDeleted(ctx)
+	if errors.Is(err, storage.ErrQuotesListEmpty) {
+		return []*storage.StorageQuote{}, nil
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
+	}
+
+	if p, ok := access.FromContext(ctx); ok && !p.Role.AtLeast(access.Editor) {
+		own := []*storage.StorageQuote{}
+		for _, q := range quotes {
+			if q.Owner == p.Subject {
+				own = append(own, q)
+			}
+		}
+		quotes = own
+	}
+
+	s.log.DebugContext(ctx, "Deleted quotes retrieved successfully", "count", len(quotes))
 
 	return quotes, nil
 }
 
+func (s *Service) Restore(ctx context.Context, id string) error {
+	s.log.DebugContext(ctx, "Restoring quote", "id", id)
+
+	intID, err := validateQuoteID(id)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "error", err)
+		return fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
+	}
+
+	if err := s.requireOwner(ctx, intID); err != nil {
+		return err
+	}
+
+	if err := s.storage.Restore(ctx, intID); err != nil {
+		if dup, ok := asDuplicate(err); ok {
+			s.log.InfoContext(ctx, "Quote already exists", "existingID", dup.ExistingID, "id", id)
+
+			return dup
+		}
+		s.log.ErrorContext(ctx, ErrRestoreQuoteFailed.Error(), "error", err, "id", id)
+
+		return fmt.Errorf("%w: %w", ErrRestoreQuoteFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Quote restored successfully", "id", id)
+
+	return nil
+}
+
+// Purge permanently removes quotes that stayed in the trash longer than retention.
+func (s *Service) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if err := s.requireRole(ctx, access.Admin); err != nil {
+		return 0, err
+	}
+
+	before := time.Now().Add(-retention)
+
+	purged, err := s.storage.Purge(ctx, before)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrPurgeQuotesFailed.Error(), "error", err)
+
+		return 0, fmt.Errorf("%w: %w", ErrPurgeQuotesFailed, err)
+	}
+
+	return purged, nil
+}
+
+// validateQuote normalizes line endings, tabs and NFC of text and author
+// and checks the quote. It is the only place quotes are validated, handlers
+// rely on it. The error wraps ErrValidateQuote and validation.Errors.
+func (s *Service) validateQuote(ctx context.Context, q *models.Quote) error {
+	q.Text = unitext.Normalize(unitext.NormalizeLines(q.Text))
+	q.Author = unitext.Normalize(unitext.NormalizeLines(q.Author))
+
+	errs := validation.FromValidator(validate.Struct(q), "")
+
+	if !unitext.InScripts(q.Author, s.scripts) {
+		errs = append(errs, s.scriptError("author"))
+	}
+	if !unitext.InScripts(q.Text, s.scripts) {
+		errs = append(errs, s.scriptError("quote"))
+	}
+
+	if len(errs) > 0 {
+		s.log.ErrorContext(ctx, ErrValidateQuote.Error(), "error", errs)
+
+		return fmt.Errorf("%w: %w", ErrValidateQuote, errs)
+	}
+
+	return nil
+}
+
+func (s *Service) scriptError(field string) validation.FieldError {
+	return validation.FieldError{
+		Field:   field,
+		Code:    validation.CodeScript,
+		Message: "contains letters of a script that is not allowed",
+		Params:  map[string]string{"allowed": strings.Join(s.settings.AllowedScripts, ",")},
+	}
+}
+
+// page converts a client page request to a storage page.
+// Limits above MaxPageSize are capped rather than rejected.
+func (s *Service) page(ctx context.Context, req PageRequest) (storage.Page, error) {
+	page := storage.Page{
+		Limit:     req.Limit,
+		WithTotal: req.WithTotal,
+	}
+
+	switch {
+	case req.Limit < 0:
+		s.log.ErrorContext(ctx, ErrInvalidPageLimit.Error(), "limit", req.Limit)
+		return page, fmt.Errorf("%w: %d", ErrInvalidPageLimit, req.Limit)
+	case req.Limit == 0:
+		page.Limit = DefaultPageSize
+	case req.Limit > MaxPageSize:
+		page.Limit = MaxPageSize
+	}
+
+	if req.Cursor != "" {
+		c, err := cursor.Decode(req.Cursor)
+		if err != nil {
+			s.log.ErrorContext(ctx, ErrInvalidCursor.Error(), "cursor", req.Cursor)
+			return page, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		page.AfterID = c.ID
+		page.AfterRank = c.Rank
+	}
+
+	return page, nil
+}
+
+func newQuotesPage(p *storage.QuotesPage) *QuotesPage {
+	page := &QuotesPage{
+		Quotes: p.Quotes,
+		Total:  p.Total,
+	}
+
+	if page.Quotes == nil {
+		page.Quotes = []*storage.StorageQuote{}
+	}
+
+	if p.HasMore && len(p.Quotes) > 0 {
+		page.NextCursor = cursor.Encode(cursor.Cursor{
+			ID:   p.Quotes[len(p.Quotes)-1].Id,
+			Rank: p.LastRank,
+		})
+	}
+
+	return page
+}
+
+// NormalizeAuthor trims the author name and collapses inner whitespace.
+func NormalizeAuthor(author string) string {
+	return strings.Join(strings.Fields(unitext.Normalize(author)), " ")
+}
+
 func validateQuoteID(id string) (int, error) {
 	if id == "" {
 		return 0, fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
 	}
 
 	intID, err := strconv.Atoi(id)
-	if err != nil {
+	if err != nil || intID <= 0 {
 
 		return 0, fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
 	}
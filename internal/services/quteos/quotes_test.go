@@ -2,10 +2,18 @@ package quteos
 
 import (
 	"app/internal/domain/models"
+	"app/internal/lib/quoteio"
+	"app/internal/lib/unitext"
+	"app/internal/lib/validation"
 	"app/internal/storage"
+	"app/internal/storage/memory"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/brianvoe/gofakeit"
@@ -41,14 +49,14 @@ func (m *mockStorage) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-func (m *mockStorage) Get(ctx context.Context, id int) (*models.Quote, error) {
+func (m *mockStorage) Get(ctx context.Context, id int) (*storage.StorageQuote, error) {
 
 	res, exists := m.data[id]
 	if !exists {
 		return nil, storage.ErrQuoteNotFound
 	}
 
-	return &res, storage.ErrQuoteNotFound
+	return &storage.StorageQuote{Quote: res, Id: id}, storage.ErrQuoteNotFound
 }
 
 func TestService_Save(t *testing.T) {
@@ -157,7 +165,7 @@ func TestService_Save(t *testing.T) {
 				log:     tt.fields.log,
 			}
 
-			_, err := s.Save(tt.args.ctx, tt.args.q)
+			_, err := s.Save(tt.args.ctx, tt.args.q, false)
 			if err != nil {
 				if tt.wantErr {
 					if !errors.Is(err, tt.err) {
@@ -270,3 +278,263 @@ func TestService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestDailyIndex_NoRepeatsWithinCycle(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 12, 100} {
+		for cycle := 0; cycle < 3; cycle++ {
+			seen := make(map[int]bool)
+
+			for day := cycle * n; day < (cycle+1)*n; day++ {
+				i := dailyIndex(day, n)
+				if i < 0 || i >= n {
+					t.Fatalf("dailyIndex(%d, %d) = %d, out of range", day, n, i)
+				}
+				if seen[i] {
+					t.Fatalf("dailyIndex(%d, %d) = %d repeats within cycle %d", day, n, i, cycle)
+				}
+				seen[i] = true
+			}
+		}
+	}
+}
+
+func TestService_ValidateQuote(t *testing.T) {
+	tests := []struct {
+		name    string
+		scripts []string
+		q       models.Quote
+		wantErr bool
+	}{
+		{
+			name: "russian quote with typographic dash",
+			q:    models.Quote{Text: "Жизнь проста — но мы всё усложняем.", Author: "Конфуций"},
+		},
+		{
+			name: "accented author in combining form",
+			q:    models.Quote{Text: "Love is the only reality.", Author: "Antoine de Saint-Exupe\u0301ry"},
+		},
+		{
+			name: "length in characters, not bytes",
+			// 100 two byte letters
+			q: models.Quote{Text: "Короткая цитата.", Author: strings.Repeat("Я", 100)},
+		},
+		{
+			name:    "too short in characters",
+			q:       models.Quote{Text: "Да.", Author: "Я"},
+			wantErr: true,
+		},
+		{
+			name:    "invisible character",
+			q:       models.Quote{Text: "Hidden\u200b text", Author: "Author"},
+			wantErr: true,
+		},
+		{
+			name: "windows line endings and tabs",
+			q:    models.Quote{Text: "First line,\r\n\tsecond line.", Author: "Author"},
+		},
+		{
+			name:    "control character",
+			q:       models.Quote{Text: "Bell\a text", Author: "Author"},
+			wantErr: true,
+		},
+		{
+			name:    "script is not allowed",
+			scripts: []string{"Latin", "Cyrillic"},
+			q:       models.Quote{Text: "Γνῶθι σεαυτόν", Author: "Thales"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(new(storage.Storage), slog.Default(), Settings{AllowedScripts: tt.scripts})
+
+			err := s.validateQuote(context.Background(), &tt.q)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("Service.validateQuote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidateQuote) {
+				t.Errorf("Service.validateQuote() error = %v, expected %v", err, ErrValidateQuote)
+			}
+
+			var errs validation.Errors
+			if err != nil && (!errors.As(err, &errs) || len(errs) == 0) {
+				t.Errorf("Service.validateQuote() error = %v, expected field errors", err)
+			}
+		})
+	}
+}
+
+// newMemoryService returns a service over an empty in-memory storage.
+func newMemoryService(t *testing.T, settings Settings) *Service {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var store storage.Storage = memory.New(log)
+
+	return New(&store, log, settings)
+}
+
+func TestService_ListByAuthor(t *testing.T) {
+	s := newMemoryService(t, Settings{})
+	ctx := context.Background()
+
+	// 60 Cyrillic letters are 120 bytes but only 60 characters
+	long := strings.Repeat("Я", 60)
+	if _, err := s.Save(ctx, &models.Quote{Text: "Жизнь проста.", Author: long}, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	page, err := s.ListByAuthor(ctx, long, "", PageRequest{Limit: 10})
+	if err != nil || len(page.Quotes) != 1 {
+		t.Errorf("ListByAuthor() = %v, %v, expected the quote of the long author", page, err)
+	}
+
+	for _, author := range []string{"Я", strings.Repeat("Я", 101)} {
+		if _, err := s.ListByAuthor(ctx, author, "", PageRequest{Limit: 10}); !errors.Is(err, ErrInvalidAuthorName) {
+			t.Errorf("ListByAuthor(%d characters) error = %v, expected %v", unitext.Len(author), err, ErrInvalidAuthorName)
+		}
+	}
+
+	err = s.Export(ctx, ExportFilter{Author: "Я"}, func(*storage.StorageQuote) error { return nil })
+	if !errors.Is(err, ErrInvalidAuthorName) {
+		t.Errorf("Export() error = %v, expected %v", err, ErrInvalidAuthorName)
+	}
+}
+
+func TestService_Trash(t *testing.T) {
+	s := newMemoryService(t, Settings{})
+	ctx := context.Background()
+
+	quotes, err := s.Trash(ctx)
+	if err != nil || quotes == nil || len(quotes) != 0 {
+		t.Fatalf("Trash() = %v, %v, expected an empty list", quotes, err)
+	}
+
+	saved, err := s.Save(ctx, &models.Quote{Text: "Жизнь проста.", Author: "Конфуций"}, false)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := s.Delete(ctx, strconv.Itoa(saved.ID)); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if quotes, err := s.Trash(ctx); err != nil || len(quotes) != 1 {
+		t.Errorf("Trash() = %v, %v, expected the deleted quote", quotes, err)
+	}
+}
+
+func TestService_Search(t *testing.T) {
+	s := newMemoryService(t, Settings{})
+	ctx := context.Background()
+
+	if _, err := s.Save(ctx, &models.Quote{Text: "Знание — сила.", Author: "Фрэнсис Бэкон"}, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		lang     string
+		expected error
+	}{
+		{name: "empty", query: "", expected: ErrInvalidSearchQuery},
+		{name: "blank", query: "   ", expected: ErrInvalidSearchQuery},
+		{name: "too long", query: strings.Repeat("я", 201), expected: ErrInvalidSearchQuery},
+		{name: "longest", query: strings.Repeat("я", 200)},
+		{name: "unknown language", query: "сила", lang: "de", expected: ErrUnsupportedSearchLang},
+		{name: "default language", query: "сила"},
+		{name: "russian", query: "сила", lang: storage.SearchRussian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := s.Search(ctx, tt.query, tt.lang, 10)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Search() error = %v, expected %v", err, tt.expected)
+			}
+			if err == nil && results == nil {
+				t.Error("Search() = nil, expected an empty list at least")
+			}
+		})
+	}
+
+	if results, _ := s.Search(ctx, "сила", storage.SearchRussian, 10); len(results) != 1 {
+		t.Errorf("Search() returned %d quotes, expected 1", len(results))
+	}
+}
+
+func TestService_DailyQuote(t *testing.T) {
+	s := newMemoryService(t, Settings{QuoteSimilarity: 1})
+	ctx := context.Background()
+
+	save := func(n int) {
+		for i := range n {
+			if _, err := s.Save(ctx, &models.Quote{Text: fmt.Sprintf("Quote number %d of %d", i, n), Author: "Anonymous"}, false); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		}
+	}
+
+	save(3)
+	first, err := s.DailyQuote(ctx, "")
+	if err != nil {
+		t.Fatalf("DailyQuote() error = %v", err)
+	}
+
+	// the pick stays for the day even when the pool grows
+	save(5)
+	if daily, err := s.DailyQuote(ctx, ""); err != nil || daily.Quote.Id != first.Quote.Id {
+		t.Fatalf("DailyQuote() after saving = %v, %v, expected quote %d", daily, err, first.Quote.Id)
+	}
+
+	if err := s.Delete(ctx, strconv.Itoa(first.Quote.Id)); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if daily, err := s.DailyQuote(ctx, ""); err != nil || daily.Quote.Id == first.Quote.Id {
+		t.Errorf("DailyQuote() after deleting the pick = %v, %v, expected another quote", daily, err)
+	}
+}
+
+// racingStorage misses stored quotes when the import looks for duplicates,
+// as if they were saved by another request right after the lookup.
+type racingStorage struct {
+	storage.Storage
+}
+
+func (racingStorage) FindByFingerprints(ctx context.Context, fingerprints []string) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func TestService_Import_ConcurrentDuplicate(t *testing.T) {
+	s := newMemoryService(t, Settings{QuoteSimilarity: 1})
+	s.storage = racingStorage{s.storage}
+	ctx := context.Background()
+
+	saved, err := s.Save(ctx, &models.Quote{Text: "Life is simple", Author: "Confucius"}, false)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	rows := []quoteio.Row{
+		{Number: 1, Quote: models.Quote{Text: "Know thyself", Author: "Socrates"}},
+		{Number: 2, Quote: models.Quote{Text: "Life is simple", Author: "Confucius"}},
+	}
+	duplicate := (&DuplicateQuoteError{ExistingID: saved.ID}).Error()
+
+	report, err := s.Import(ctx, rows, ImportAtomic)
+	if !errors.Is(err, ErrImportRejected) {
+		t.Fatalf("Import() atomic error = %v, expected %v", err, ErrImportRejected)
+	}
+	if report.Created != 0 || report.Failed != 1 || report.Rows[1].Error != duplicate {
+		t.Errorf("Import() atomic report = %+v, expected row 2 reported as a duplicate", report)
+	}
+
+	report, err = s.Import(ctx, rows, ImportBestEffort)
+	if err != nil {
+		t.Fatalf("Import() best effort error = %v", err)
+	}
+	if report.Created != 1 || report.Failed != 1 || report.Rows[0].ID == 0 || report.Rows[1].Error != duplicate {
+		t.Errorf("Import() best effort report = %+v, expected row 1 created and row 2 a duplicate", report)
+	}
+}
@@ -0,0 +1,111 @@
+package quteos
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/domain/models"
+	"app/internal/lib/fingerprint"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSimilarFailed = fmt.Errorf("failed to find similar quotes")
+	ErrSimilarQuote  = errcode.New(errcode.SimilarQuote, "quote is similar to stored quotes")
+)
+
+const (
+	// MinSimilarScore is the lowest score listed by Similar, the default
+	// pg_trgm similarity threshold.
+	MinSimilarScore = 0.3
+
+	// nearDuplicatesLimit is the number of similar quotes reported on save.
+	nearDuplicatesLimit = 5
+)
+
+// SimilarQuoteError is returned by strict saves of quotes similar to stored ones.
+type SimilarQuoteError struct {
+	Similar []*storage.SimilarQuote
+}
+
+func (e *SimilarQuoteError) Error() string {
+	return fmt.Sprintf("quote is similar to %d stored quotes", len(e.Similar))
+}
+
+func (e *SimilarQuoteError) Unwrap() error {
+	return ErrSimilarQuote
+}
+
+func (e *SimilarQuoteError) Details() any {
+	return map[string]any{"similar": e.Similar}
+}
+
+// Similar lists the quotes closest to the quote with the given id.
+func (s *Service) Similar(ctx context.Context, id string, limit int) ([]*storage.SimilarQuote, error) {
+	s.log.DebugContext(ctx, "Finding similar quotes", "id", id, "limit", limit)
+
+	intID, err := validateQuoteID(id)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
+	}
+
+	page, err := s.page(ctx, PageRequest{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.storage.Get(ctx, intID)
+	if err != nil {
+		if errors.Is(err, storage.ErrQuoteNotFound) {
+			s.log.ErrorContext(ctx, ErrSimilarFailed.Error(), "err", storage.ErrQuoteNotFound, "id", id)
+
+			return nil, fmt.Errorf("%w: %w", ErrSimilarFailed, storage.ErrQuoteNotFound)
+		}
+		s.log.ErrorContext(ctx, ErrSimilarFailed.Error(), "error", err, "id", id)
+
+		return nil, fmt.Errorf("%w: %w", ErrSimilarFailed, err)
+	}
+
+	similar, err := s.storage.Similar(ctx, quote.Text, intID, MinSimilarScore, page.Limit)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrSimilarFailed.Error(), "error", err, "id", id)
+
+		return nil, fmt.Errorf("%w: %w", ErrSimilarFailed, err)
+	}
+
+	if similar == nil {
+		similar = []*storage.SimilarQuote{}
+	}
+
+	s.log.DebugContext(ctx, "Similar quotes found", "count", len(similar))
+
+	return similar, nil
+}
+
+// nearDuplicates returns stored quotes scoring at least QuoteSimilarity
+// against q. Exact duplicates are reported as *DuplicateQuoteError.
+func (s *Service) nearDuplicates(ctx context.Context, q *models.Quote) ([]*storage.SimilarQuote, error) {
+	if s.settings.QuoteSimilarity <= 0 {
+		return nil, nil
+	}
+
+	similar, err := s.storage.Similar(ctx, q.Text, 0, s.settings.QuoteSimilarity, nearDuplicatesLimit)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrSaveQuoteFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrSaveQuoteFailed, err)
+	}
+
+	fp := fingerprint.Of(q.Text, q.Author)
+	for _, sq := range similar {
+		if fingerprint.Of(sq.Text, sq.Author) == fp {
+			s.log.InfoContext(ctx, "Quote already exists", "existingID", sq.Id)
+
+			return nil, &DuplicateQuoteError{ExistingID: sq.Id}
+		}
+	}
+
+	return similar, nil
+}
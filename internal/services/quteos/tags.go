@@ -0,0 +1,166 @@
+package quteos
+
+import (
+	"app/internal/domain/errcode"
+	"app/internal/domain/models"
+	"app/internal/lib/unitext"
+	"app/internal/lib/validation"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrTagQuoteFailed  = fmt.Errorf("failed to update quote tags")
+	ErrListTagsFailed  = fmt.Errorf("failed to list tags")
+	ErrInvalidTags     = errcode.New(errcode.ValidationFailed, "invalid tags, must be 1 to 10 tags of 2 to 30 visible characters")
+	ErrInvalidTagMatch = errcode.New(errcode.InvalidArgument, "invalid tag match mode, must be any or all")
+)
+
+// tagsRules validates a non empty list of normalized tags, same as models.Quote.Tags.
+const tagsRules = "min=1,max=10,dive,gmin=2,gmax=30,visible"
+
+// ListByTags lists quotes having any (storage.TagsAny, the default) or all
+// (storage.TagsAll) of the tags.
+func (s *Service) ListByTags(ctx context.Context, tags []string, match string, req PageRequest) (*QuotesPage, error) {
+	tags = NormalizeTags(tags)
+
+	s.log.DebugContext(ctx, "Listing quotes by tags", "tags", tags, "match", match, "limit", req.Limit, "cursor", req.Cursor)
+
+	filter, err := s.tagFilter(ctx, tags, match)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.page(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.storage.ListByTags(ctx, filter, page)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrGetQuoteFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrGetQuoteFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Quotes retrieved successfully", "count", len(quotes.Quotes))
+
+	return newQuotesPage(quotes), nil
+}
+
+// tagFilter checks normalized tags and the match mode, empty means storage.TagsAny.
+func (s *Service) tagFilter(ctx context.Context, tags []string, match string) (storage.TagFilter, error) {
+	if err := s.validateTags(ctx, tags); err != nil {
+		return storage.TagFilter{}, err
+	}
+
+	switch match {
+	case "":
+		match = storage.TagsAny
+	case storage.TagsAny, storage.TagsAll:
+	default:
+		s.log.ErrorContext(ctx, ErrInvalidTagMatch.Error(), "match", match)
+		return storage.TagFilter{}, fmt.Errorf("%w: %s", ErrInvalidTagMatch, match)
+	}
+
+	return storage.TagFilter{Tags: tags, Match: match}, nil
+}
+
+// Tags lists tags in use with the number of quotes having them.
+func (s *Service) Tags(ctx context.Context) ([]*models.Tag, error) {
+	s.log.DebugContext(ctx, "Listing tags")
+
+	tags, err := s.storage.ListTags(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrListTagsFailed.Error(), "error", err)
+
+		return nil, fmt.Errorf("%w: %w", ErrListTagsFailed, err)
+	}
+
+	if tags == nil {
+		tags = []*models.Tag{}
+	}
+
+	s.log.DebugContext(ctx, "Tags retrieved successfully", "count", len(tags))
+
+	return tags, nil
+}
+
+func (s *Service) AddTags(ctx context.Context, id string, tags []string) (*storage.StorageQuote, error) {
+	return s.changeTags(ctx, id, tags, s.storage.AddTags)
+}
+
+func (s *Service) RemoveTags(ctx context.Context, id string, tags []string) (*storage.StorageQuote, error) {
+	return s.changeTags(ctx, id, tags, s.storage.RemoveTags)
+}
+
+func (s *Service) changeTags(
+	ctx context.Context,
+	id string,
+	tags []string,
+	change func(ctx context.Context, id int, tags []string) (*storage.StorageQuote, error),
+) (*storage.StorageQuote, error) {
+	tags = NormalizeTags(tags)
+
+	s.log.DebugContext(ctx, "Changing quote tags", "id", id, "tags", tags)
+
+	intID, err := validateQuoteID(id)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidQuoteID.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidQuoteID, id)
+	}
+
+	if err := s.validateTags(ctx, tags); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireOwner(ctx, intID); err != nil {
+		return nil, err
+	}
+
+	quote, err := change(ctx, intID, tags)
+	if err != nil {
+		if errors.Is(err, storage.ErrQuoteNotFound) {
+			s.log.ErrorContext(ctx, storage.ErrQuoteNotFound.Error(), "id", id)
+
+			return nil, fmt.Errorf("%w: %s", storage.ErrQuoteNotFound, id)
+		}
+		s.log.ErrorContext(ctx, ErrTagQuoteFailed.Error(), "error", err, "id", id)
+
+		return nil, fmt.Errorf("%w: %w", ErrTagQuoteFailed, err)
+	}
+
+	s.log.DebugContext(ctx, "Quote tags changed successfully", "id", id, "tags", quote.Tags)
+
+	return quote, nil
+}
+
+func (s *Service) validateTags(ctx context.Context, tags []string) error {
+	if err := validate.Var(tags, tagsRules); err != nil {
+		s.log.ErrorContext(ctx, ErrInvalidTags.Error(), "tags", tags, "error", err)
+
+		return fmt.Errorf("%w: %w", ErrInvalidTags, validation.FromValidator(err, "tags"))
+	}
+
+	return nil
+}
+
+// NormalizeTags lowercases tags, collapses inner whitespace to dashes and
+// drops empty and duplicate ones. The result is sorted.
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+
+	for _, t := range tags {
+		t = strings.Join(strings.Fields(strings.ToLower(unitext.Normalize(t))), "-")
+		if t != "" && !slices.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	slices.Sort(normalized)
+
+	return normalized
+}
@@ -0,0 +1,113 @@
+// Package users manages the users API keys act as and the role each of them has.
+package users
+
+import (
+	"app/internal/domain/access"
+	"app/internal/domain/errcode"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+var (
+	ErrCreateUserFailed = fmt.Errorf("failed to create user")
+	ErrListUsersFailed  = fmt.Errorf("failed to list users")
+	ErrSetRoleFailed    = fmt.Errorf("failed to change user role")
+
+	ErrInvalidName = errcode.New(errcode.InvalidArgument, "invalid user name, must not be empty")
+	ErrInvalidRole = errcode.New(errcode.InvalidArgument, "invalid role, must be reader, contributor, editor or admin")
+)
+
+type Storage interface {
+	SaveUser(ctx context.Context, user *storage.User) (int, error)
+	GetUserByName(ctx context.Context, name string) (*storage.User, error)
+	ListUsers(ctx context.Context) ([]*storage.User, error)
+	SetUserRole(ctx context.Context, id int, role string) error
+}
+
+type Service struct {
+	storage Storage
+	log     *slog.Logger
+}
+
+func New(log *slog.Logger, storage Storage) *Service {
+	return &Service{
+		storage: storage,
+		log:     log,
+	}
+}
+
+// Create adds a user with the role, it returns storage.ErrUserExists when
+// the name is taken.
+func (s *Service) Create(ctx context.Context, name string, role string) (*storage.User, error) {
+	s.log.DebugContext(ctx, "Creating user", "name", name, "role", role)
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		s.log.ErrorContext(ctx, ErrInvalidName.Error())
+		return nil, ErrInvalidName
+	}
+
+	if _, ok := access.ParseRole(role); !ok {
+		s.log.ErrorContext(ctx, ErrInvalidRole.Error(), "role", role)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+
+	user := &storage.User{Name: name, Role: role}
+
+	id, err := s.storage.SaveUser(ctx, user)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserExists) {
+			return nil, err
+		}
+		s.log.ErrorContext(ctx, ErrCreateUserFailed.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrCreateUserFailed, err)
+	}
+	user.ID = id
+
+	s.log.InfoContext(ctx, "User created", "id", id, "name", name, "role", role)
+
+	return user, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*storage.User, error) {
+	users, err := s.storage.ListUsers(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, ErrListUsersFailed.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrListUsersFailed, err)
+	}
+
+	return users, nil
+}
+
+// SetRole changes the role of the user, API keys of the user get the new
+// role with their next request.
+func (s *Service) SetRole(ctx context.Context, name string, role string) error {
+	if _, ok := access.ParseRole(role); !ok {
+		s.log.ErrorContext(ctx, ErrInvalidRole.Error(), "role", role)
+		return fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+
+	user, err := s.storage.GetUserByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return err
+		}
+		s.log.ErrorContext(ctx, ErrSetRoleFailed.Error(), "error", err, "name", name)
+		return fmt.Errorf("%w: %w", ErrSetRoleFailed, err)
+	}
+
+	if err := s.storage.SetUserRole(ctx, user.ID, role); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return err
+		}
+		s.log.ErrorContext(ctx, ErrSetRoleFailed.Error(), "error", err, "name", name)
+		return fmt.Errorf("%w: %w", ErrSetRoleFailed, err)
+	}
+
+	s.log.InfoContext(ctx, "User role changed", "name", user.Name, "from", user.Role, "to", role)
+	return nil
+}
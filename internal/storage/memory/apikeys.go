@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"app/internal/storage"
+	"context"
+	"slices"
+	"time"
+)
+
+func (m *MemoryStorage) SaveAPIKey(ctx context.Context, key *storage.APIKey) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastAPIKeyID++
+
+	k := *key
+	k.ID = m.lastAPIKeyID
+	k.Scopes = slices.Clone(key.Scopes)
+	k.CreatedAt = time.Now()
+	k.RevokedAt = nil
+	m.apiKeys[k.ID] = k
+
+	m.log.DebugContext(ctx, "API key saved", "id", k.ID, "name", k.Name, "scopes", k.Scopes)
+	return k.ID, nil
+}
+
+func (m *MemoryStorage) APIKeyByHash(ctx context.Context, hash string) (*storage.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, k := range m.apiKeys {
+		if k.Hash == hash {
+			return copyAPIKey(k), nil
+		}
+	}
+
+	return nil, storage.ErrAPIKeyNotFound
+}
+
+func (m *MemoryStorage) ListAPIKeys(ctx context.Context) ([]*storage.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]*storage.APIKey, 0, len(m.apiKeys))
+	for _, k := range m.apiKeys {
+		keys = append(keys, copyAPIKey(k))
+	}
+
+	slices.SortFunc(keys, func(a, b *storage.APIKey) int { return a.ID - b.ID })
+
+	return keys, nil
+}
+
+func (m *MemoryStorage) RevokeAPIKey(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k, ok := m.apiKeys[id]
+	if !ok || k.RevokedAt != nil {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	now := time.Now()
+	k.RevokedAt = &now
+	m.apiKeys[id] = k
+
+	m.log.DebugContext(ctx, "API key revoked", "id", id)
+	return nil
+}
+
+// copyAPIKey returns a copy the caller may modify.
+func copyAPIKey(k storage.APIKey) *storage.APIKey {
+	k.Scopes = slices.Clone(k.Scopes)
+	return &k
+}
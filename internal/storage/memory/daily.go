@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"app/internal/storage"
+	"context"
+	"time"
+)
+
+func (m *MemoryStorage) Pick(ctx context.Context, pick func(count int) int) (*storage.StorageQuote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	quotes := m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt == nil })
+
+	if len(quotes) == 0 {
+		return nil, storage.ErrQuotesListEmpty
+	}
+
+	return quotes[pick(len(quotes))], nil
+}
+
+func (m *MemoryStorage) PinDaily(ctx context.Context, day time.Time, quoteID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.alive(quoteID); !ok {
+		m.log.WarnContext(ctx, "Quote not found", "id", quoteID)
+		return storage.ErrQuoteNotFound
+	}
+
+	m.pins[day.Format(time.DateOnly)] = quoteID
+
+	m.log.DebugContext(ctx, "Daily quote pinned", "day", day.Format(time.DateOnly), "id", quoteID)
+	return nil
+}
+
+func (m *MemoryStorage) UnpinDaily(ctx context.Context, day time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := day.Format(time.DateOnly)
+
+	if _, ok := m.pins[key]; !ok {
+		return storage.ErrPinNotFound
+	}
+	delete(m.pins, key)
+
+	m.log.DebugContext(ctx, "Daily quote unpinned", "day", key)
+	return nil
+}
+
+func (m *MemoryStorage) DailyPin(ctx context.Context, day time.Time) (*storage.StorageQuote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.pins[day.Format(time.DateOnly)]
+	if !ok {
+		return nil, storage.ErrPinNotFound
+	}
+
+	q, ok := m.alive(id)
+	if !ok {
+		return nil, storage.ErrPinNotFound
+	}
+
+	return &q, nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"app/internal/storage"
+	"context"
+	"math"
+	"sort"
+)
+
+// Export reuses the list methods, the whole in-memory collection is small
+// enough to be copied before fn is called without holding the lock.
+func (m *MemoryStorage) Export(ctx context.Context, filter storage.ExportFilter, fn func(q *storage.StorageQuote) error) error {
+	all := storage.Page{Limit: math.MaxInt}
+
+	var (
+		page *storage.QuotesPage
+		err  error
+	)
+
+	switch {
+	case filter.Author != nil:
+		page, err = m.ListByAuthor(ctx, *filter.Author, all)
+	case filter.Tags != nil:
+		page, err = m.ListByTags(ctx, *filter.Tags, all)
+	default:
+		page, err = m.List(ctx, all)
+	}
+	if err != nil {
+		return err
+	}
+
+	quotes := page.Quotes
+
+	// ranked author matches come best first
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].Id < quotes[j].Id
+	})
+
+	for _, q := range quotes {
+		if err := fn(q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,579 @@
+package memory
+
+import (
+	"app/internal/domain/models"
+	"app/internal/lib/fingerprint"
+	"app/internal/lib/minhash"
+	"app/internal/storage"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type MemoryStorage struct {
+	mu     sync.RWMutex
+	quotes map[int]storage.StorageQuote
+	lastID int
+
+	authors      map[int]string // author names by id
+	authorByName map[string]int // author ids by name
+	lastAuthorID int
+
+	pins         map[string]int            // pinned daily quote ids by date
+	fingerprints map[int]string            // quote fingerprints by id
+	signatures   map[int]minhash.Signature // MinHash signatures of quote trigrams by id
+
+	apiKeys      map[int]storage.APIKey
+	lastAPIKeyID int
+	users        map[int]storage.User
+	lastUserID   int
+
+	log *slog.Logger
+}
+
+func New(log *slog.Logger) *MemoryStorage {
+	log.Debug("Using in-memory storage")
+
+	return &MemoryStorage{
+		quotes:       make(map[int]storage.StorageQuote),
+		authors:      make(map[int]string),
+		authorByName: make(map[string]int),
+		pins:         make(map[string]int),
+		fingerprints: make(map[int]string),
+		signatures:   make(map[int]minhash.Signature),
+		apiKeys:      make(map[int]storage.APIKey),
+		users:        make(map[int]storage.User),
+		log:          log,
+	}
+}
+
+func (m *MemoryStorage) Save(ctx context.Context, quote string, author string, tags []string, owner string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.duplicateOf(fingerprint.Of(quote, author), 0); ok {
+		m.log.InfoContext(ctx, "Duplicate quote", "existingID", existing)
+		return 0, &storage.DuplicateError{ExistingID: existing}
+	}
+
+	id := m.save(quote, author, tags, owner)
+
+	m.log.DebugContext(ctx, "Quote saved successfully", "id", id, "quote", quote, "author", author, "tags", tags)
+	return id, nil
+}
+
+func (m *MemoryStorage) Import(ctx context.Context, quotes []*models.Quote, owner string, atomic bool) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// duplicates are looked up before anything is saved, so atomic
+	// imports can fail as a whole
+	duplicates := make(map[int]*storage.DuplicateError)
+	seen := make(map[string]int) // indexes by fingerprint
+
+	for i, q := range quotes {
+		fp := fingerprint.Of(q.Text, q.Author)
+
+		if existing, ok := m.duplicateOf(fp, 0); ok {
+			duplicates[i] = &storage.DuplicateError{ExistingID: existing}
+		} else if _, ok := seen[fp]; ok {
+			duplicates[i] = &storage.DuplicateError{}
+		} else {
+			seen[fp] = i
+		}
+	}
+
+	if len(duplicates) > 0 && atomic {
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToImportQuotes, &storage.ImportDuplicatesError{Duplicates: duplicates})
+	}
+
+	ids := make([]int, len(quotes))
+	for i, q := range quotes {
+		if duplicates[i] == nil {
+			ids[i] = m.save(q.Text, q.Author, q.Tags, owner)
+		}
+	}
+
+	if len(duplicates) > 0 {
+		// repeats of an earlier quote of the import point at it
+		for i, dup := range duplicates {
+			if dup.ExistingID == 0 {
+				dup.ExistingID = ids[seen[fingerprint.Of(quotes[i].Text, quotes[i].Author)]]
+			}
+		}
+
+		return ids, fmt.Errorf("%w: %w", storage.ErrFailedToImportQuotes, &storage.ImportDuplicatesError{Duplicates: duplicates})
+	}
+
+	m.log.DebugContext(ctx, "Quotes imported successfully", "count", len(ids))
+	return ids, nil
+}
+
+func (m *MemoryStorage) FindByFingerprints(ctx context.Context, fingerprints []string) (map[string]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make(map[string]int)
+	for _, fp := range fingerprints {
+		if id, ok := m.duplicateOf(fp, 0); ok {
+			ids[fp] = id
+		}
+	}
+
+	return ids, nil
+}
+
+func (m *MemoryStorage) Delete(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.alive(id)
+	if !ok {
+		m.log.WarnContext(ctx, "Quote not found", "id", id)
+		return storage.ErrQuoteNotFound
+	}
+
+	now := time.Now()
+	q.DeletedAt = &now
+	m.quotes[id] = q
+
+	m.log.DebugContext(ctx, "Quote deleted successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryStorage) Update(ctx context.Context, id int, quote string, author string, version int) (*storage.StorageQuote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.alive(id)
+	if !ok {
+		m.log.WarnContext(ctx, "Quote not found", "id", id)
+		return nil, storage.ErrQuoteNotFound
+	}
+
+	if q.Version != version {
+		m.log.WarnContext(ctx, "Quote version conflict", "id", id, "version", version)
+		return nil, storage.ErrVersionConflict
+	}
+
+	fp := fingerprint.Of(quote, author)
+	if existing, ok := m.duplicateOf(fp, id); ok {
+		m.log.InfoContext(ctx, "Duplicate quote", "existingID", existing)
+		return nil, &storage.DuplicateError{ExistingID: existing}
+	}
+
+	m.fingerprints[id] = fp
+	m.signatures[id] = minhash.Of(trigrams(quote))
+	q.AuthorID = m.authorID(author)
+	q.Text = quote
+	q.Author = author
+	q.Version++
+	m.quotes[id] = q
+
+	m.log.DebugContext(ctx, "Quote updated successfully", "id", id, "version", q.Version)
+	return &q, nil
+}
+
+func (m *MemoryStorage) Get(ctx context.Context, id int) (*storage.StorageQuote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q, ok := m.alive(id)
+	if !ok {
+		return nil, storage.ErrQuoteNotFound
+	}
+
+	return &q, nil
+}
+
+func (m *MemoryStorage) QuoteOwner(ctx context.Context, id int) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q, ok := m.quotes[id]
+	if !ok {
+		return "", storage.ErrQuoteNotFound
+	}
+
+	return q.Owner, nil
+}
+
+func (m *MemoryStorage) List(ctx context.Context, page storage.Page) (*storage.QuotesPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	quotes := m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt == nil })
+
+	return paginate(quotes, page), nil
+}
+
+func (m *MemoryStorage) ListByAuthor(ctx context.Context, author storage.AuthorFilter, page storage.Page) (*storage.QuotesPage, error) {
+	var rank func(name string) (float64, bool)
+
+	lowerName := strings.ToLower(author.Name)
+
+	switch author.Match {
+	case storage.MatchExact:
+		rank = func(name string) (float64, bool) { return 1, name == author.Name }
+
+	case storage.MatchICase:
+		rank = func(name string) (float64, bool) {
+			if name == author.Name {
+				return 1, true
+			}
+			return 0.5, strings.ToLower(name) == lowerName
+		}
+
+	case storage.MatchPrefix:
+		rank = func(name string) (float64, bool) {
+			if !strings.HasPrefix(strings.ToLower(name), lowerName) {
+				return 0, false
+			}
+			return float64(utf8.RuneCountInString(author.Name)) / float64(utf8.RuneCountInString(name)), true
+		}
+
+	case storage.MatchFuzzy:
+		rank = func(name string) (float64, bool) {
+			sim := similarity(name, author.Name)
+			return sim, sim >= author.Threshold
+		}
+
+	default:
+		return nil, storage.ErrFailedToListByAuthor
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ranked []rankedQuote
+	for _, q := range m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt == nil }) {
+		if r, ok := rank(q.Author); ok {
+			ranked = append(ranked, rankedQuote{quote: q, rank: r})
+		}
+	}
+
+	if author.Match == storage.MatchExact {
+		quotes := make([]*storage.StorageQuote, 0, len(ranked))
+		for _, r := range ranked {
+			quotes = append(quotes, r.quote)
+		}
+		return paginate(quotes, page), nil
+	}
+
+	return paginateRanked(ranked, page), nil
+}
+
+func (m *MemoryStorage) ListByAuthorID(ctx context.Context, authorID int, page storage.Page) (*storage.QuotesPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	quotes := m.collect(func(q storage.StorageQuote) bool {
+		return q.DeletedAt == nil && q.AuthorID == authorID
+	})
+
+	return paginate(quotes, page), nil
+}
+
+func (m *MemoryStorage) ListAuthors(ctx context.Context, page storage.Page) (*storage.AuthorsPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := m.quoteCounts()
+
+	var authors []*models.Author
+	for id, count := range counts {
+		authors = append(authors, &models.Author{
+			ID:          id,
+			Name:        m.authors[id],
+			QuotesCount: count,
+		})
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		return authors[i].ID < authors[j].ID
+	})
+
+	result := &storage.AuthorsPage{}
+
+	if page.WithTotal {
+		total := len(authors)
+		result.Total = &total
+	}
+
+	start := sort.Search(len(authors), func(i int) bool {
+		return authors[i].ID > page.AfterID
+	})
+	authors = authors[start:]
+
+	if len(authors) > page.Limit {
+		authors = authors[:page.Limit]
+		result.HasMore = true
+	}
+	result.Authors = authors
+
+	return result, nil
+}
+
+func (m *MemoryStorage) GetAuthor(ctx context.Context, id int) (*models.Author, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, ok := m.authors[id]
+	if !ok {
+		return nil, storage.ErrAuthorNotFound
+	}
+
+	return &models.Author{
+		ID:          id,
+		Name:        name,
+		QuotesCount: m.quoteCounts()[id],
+	}, nil
+}
+
+func (m *MemoryStorage) Random(ctx context.Context, filter storage.RandomFilter) (*models.Quote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	quotes := m.collect(func(q storage.StorageQuote) bool {
+		return q.DeletedAt == nil &&
+			(filter.Author == "" || q.Author == filter.Author) &&
+			(filter.Tag == "" || slices.Contains(q.Tags, filter.Tag)) &&
+			(filter.MaxLength == 0 || utf8.RuneCountInString(q.Text) <= filter.MaxLength)
+	})
+
+	if len(quotes) == 0 {
+		return nil, storage.ErrQuotesListEmpty
+	}
+
+	return &quotes[rand.IntN(len(quotes))].Quote, nil
+}
+
+func (m *MemoryStorage) ListDeleted(ctx context.Context) ([]*storage.StorageQuote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	quotes := m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt != nil })
+
+	if len(quotes) == 0 {
+		m.log.InfoContext(ctx, "Trash is empty")
+		return nil, storage.ErrQuotesListEmpty
+	}
+
+	// most recently deleted first, same as the postgres backend
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].DeletedAt.After(*quotes[j].DeletedAt)
+	})
+
+	return quotes, nil
+}
+
+func (m *MemoryStorage) Restore(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.quotes[id]
+	if !ok || q.DeletedAt == nil {
+		m.log.WarnContext(ctx, "Quote not found in trash", "id", id)
+		return storage.ErrQuoteNotFound
+	}
+
+	if existing, ok := m.duplicateOf(m.fingerprints[id], id); ok {
+		m.log.InfoContext(ctx, "Duplicate quote", "existingID", existing)
+		return &storage.DuplicateError{ExistingID: existing}
+	}
+
+	q.DeletedAt = nil
+	m.quotes[id] = q
+
+	m.log.DebugContext(ctx, "Quote restored successfully", "id", id)
+	return nil
+}
+
+func (m *MemoryStorage) Purge(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	purged := 0
+	for id, q := range m.quotes {
+		if q.DeletedAt != nil && q.DeletedAt.Before(before) {
+			delete(m.quotes, id)
+			delete(m.fingerprints, id)
+			delete(m.signatures, id)
+			purged++
+		}
+	}
+
+	m.log.DebugContext(ctx, "Deleted quotes purged", "count", purged, "before", before)
+	return purged, nil
+}
+
+func (m *MemoryStorage) Close() {
+	m.log.Debug("In-memory storage closed")
+}
+
+// save stores a new quote and returns its id. Caller must hold the write lock.
+func (m *MemoryStorage) save(quote string, author string, tags []string, owner string) int {
+	authorID := m.authorID(author)
+
+	m.lastID++
+	m.quotes[m.lastID] = storage.StorageQuote{
+		Quote: models.Quote{
+			Text:   quote,
+			Author: author,
+			Tags:   addTags(nil, tags),
+		},
+		Id:       m.lastID,
+		AuthorID: authorID,
+		Version:  1,
+		Owner:    owner,
+	}
+	m.fingerprints[m.lastID] = fingerprint.Of(quote, author)
+	m.signatures[m.lastID] = minhash.Of(trigrams(quote))
+
+	return m.lastID
+}
+
+// duplicateOf returns the id of a not deleted quote other than except with
+// the fingerprint. Caller must hold the lock.
+func (m *MemoryStorage) duplicateOf(fp string, except int) (int, bool) {
+	for id, other := range m.fingerprints {
+		if id == except || other != fp {
+			continue
+		}
+		if _, ok := m.alive(id); ok {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// authorID returns the id of the author with the given name, creating it when needed.
+// Names are unique after the whitespace normalization done by the service.
+// Caller must hold the write lock.
+func (m *MemoryStorage) authorID(name string) int {
+	if id, ok := m.authorByName[name]; ok {
+		return id
+	}
+
+	m.lastAuthorID++
+	m.authors[m.lastAuthorID] = name
+	m.authorByName[name] = m.lastAuthorID
+
+	return m.lastAuthorID
+}
+
+// quoteCounts returns the number of not deleted quotes by author id.
+// Caller must hold the read lock.
+func (m *MemoryStorage) quoteCounts() map[int]int {
+	counts := make(map[int]int)
+	for _, q := range m.quotes {
+		if q.DeletedAt == nil {
+			counts[q.AuthorID]++
+		}
+	}
+
+	return counts
+}
+
+// alive returns the quote with the given id unless it is missing or in the trash.
+// Caller must hold the lock.
+func (m *MemoryStorage) alive(id int) (storage.StorageQuote, bool) {
+	q, ok := m.quotes[id]
+	if !ok || q.DeletedAt != nil {
+		return storage.StorageQuote{}, false
+	}
+
+	return q, true
+}
+
+// collect returns quotes matching the predicate ordered by id.
+// Caller must hold the read lock.
+func (m *MemoryStorage) collect(match func(storage.StorageQuote) bool) []*storage.StorageQuote {
+	var quotes []*storage.StorageQuote
+
+	for _, q := range m.quotes {
+		if !match(q) {
+			continue
+		}
+		quotes = append(quotes, &q)
+	}
+
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].Id < quotes[j].Id
+	})
+
+	return quotes
+}
+
+// paginate cuts one keyset page out of quotes ordered by id.
+func paginate(quotes []*storage.StorageQuote, page storage.Page) *storage.QuotesPage {
+	result := &storage.QuotesPage{}
+
+	if page.WithTotal {
+		total := len(quotes)
+		result.Total = &total
+	}
+
+	start := sort.Search(len(quotes), func(i int) bool {
+		return quotes[i].Id > page.AfterID
+	})
+	quotes = quotes[start:]
+
+	if len(quotes) > page.Limit {
+		quotes = quotes[:page.Limit]
+		result.HasMore = true
+	}
+	result.Quotes = quotes
+
+	return result
+}
+
+type rankedQuote struct {
+	quote *storage.StorageQuote
+	rank  float64
+}
+
+// paginateRanked cuts one keyset page out of quotes ordered by rank descending and id.
+func paginateRanked(quotes []rankedQuote, page storage.Page) *storage.QuotesPage {
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].rank > quotes[j].rank
+	})
+
+	result := &storage.QuotesPage{}
+
+	if page.WithTotal {
+		total := len(quotes)
+		result.Total = &total
+	}
+
+	start := 0
+	if page.AfterID > 0 {
+		start = sort.Search(len(quotes), func(i int) bool {
+			q := quotes[i]
+			return q.rank < page.AfterRank || (q.rank == page.AfterRank && q.quote.Id > page.AfterID)
+		})
+	}
+	quotes = quotes[start:]
+
+	if len(quotes) > page.Limit {
+		quotes = quotes[:page.Limit]
+		result.HasMore = true
+	}
+
+	for _, q := range quotes {
+		result.Quotes = append(result.Quotes, q.quote)
+	}
+	if len(quotes) > 0 {
+		result.LastRank = quotes[len(quotes)-1].rank
+	}
+
+	return result
+}
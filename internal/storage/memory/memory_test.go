@@ -0,0 +1,469 @@
+package memory
+
+import (
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+var firstPage = storage.Page{Limit: 10}
+
+func TestMemoryStorage_EmptyErrors(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	if page, err := m.List(ctx, firstPage); err != nil || len(page.Quotes) != 0 || page.HasMore {
+		t.Errorf("List() = %v, %v, expected empty page", page, err)
+	}
+
+	if page, err := m.ListByAuthor(ctx, storage.AuthorFilter{Name: "Nobody", Match: storage.MatchExact}, firstPage); err != nil || len(page.Quotes) != 0 || page.HasMore {
+		t.Errorf("ListByAuthor() = %v, %v, expected empty page", page, err)
+	}
+
+	if _, err := m.Random(ctx, storage.RandomFilter{}); !errors.Is(err, storage.ErrQuotesListEmpty) {
+		t.Errorf("Random() error = %v, expected %v", err, storage.ErrQuotesListEmpty)
+	}
+
+	if _, err := m.Get(ctx, 1); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Get() error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+
+	if err := m.Delete(ctx, 1); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Delete() error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+}
+
+func TestMemoryStorage_SaveListDelete(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	first, _ := m.Save(ctx, "Life is simple", "Confucius", nil, "")
+	second, _ := m.Save(ctx, "Know thyself", "Socrates", nil, "")
+	third, _ := m.Save(ctx, "Real knowledge", "Confucius", nil, "")
+
+	page, err := m.List(ctx, firstPage)
+	if err != nil {
+		t.Fatalf("List() unexpected error = %v", err)
+	}
+	list := page.Quotes
+	if len(list) != 3 || list[0].Id != first || list[1].Id != second || list[2].Id != third {
+		t.Errorf("List() returned %v, expected ids %d, %d, %d in order", list, first, second, third)
+	}
+
+	byAuthor, err := m.ListByAuthor(ctx, storage.AuthorFilter{Name: "Confucius", Match: storage.MatchExact}, firstPage)
+	if err != nil {
+		t.Fatalf("ListByAuthor() unexpected error = %v", err)
+	}
+	if len(byAuthor.Quotes) != 2 {
+		t.Errorf("ListByAuthor() returned %d quotes, expected 2", len(byAuthor.Quotes))
+	}
+
+	if err := m.Delete(ctx, second); err != nil {
+		t.Fatalf("Delete() unexpected error = %v", err)
+	}
+
+	if _, err := m.Get(ctx, second); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Get() after delete error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+
+	q, err := m.Random(ctx, storage.RandomFilter{})
+	if err != nil {
+		t.Fatalf("Random() unexpected error = %v", err)
+	}
+	if q.Author != "Confucius" {
+		t.Errorf("Random() returned deleted quote %v", q)
+	}
+}
+
+func TestMemoryStorage_ConcurrentSave(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Save(ctx, fmt.Sprintf("Concurrent quote %d", i), "Author", nil, "")
+		}()
+	}
+	wg.Wait()
+
+	page, err := m.List(ctx, storage.Page{Limit: 100, WithTotal: true})
+	if err != nil {
+		t.Fatalf("List() unexpected error = %v", err)
+	}
+	if len(page.Quotes) != 50 || *page.Total != 50 {
+		t.Errorf("List() returned %d quotes, expected 50", len(page.Quotes))
+	}
+}
+
+func TestMemoryStorage_UpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	id, _ := m.Save(ctx, "Life is simple", "Confucius", nil, "")
+
+	updated, err := m.Update(ctx, id, "Life is really simple", "Confucius", 1)
+	if err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if updated.Version != 2 || updated.Text != "Life is really simple" {
+		t.Errorf("Update() returned %v, expected version 2 with new text", updated)
+	}
+
+	if _, err := m.Update(ctx, id, "Stale write", "Confucius", 1); !errors.Is(err, storage.ErrVersionConflict) {
+		t.Errorf("Update() with stale version error = %v, expected %v", err, storage.ErrVersionConflict)
+	}
+
+	if _, err := m.Update(ctx, id+1, "Missing", "Confucius", 1); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Update() of missing quote error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+}
+
+func TestMemoryStorage_TrashRestorePurge(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	kept, _ := m.Save(ctx, "Life is simple", "Confucius", nil, "")
+	purged, _ := m.Save(ctx, "Know thyself", "Socrates", nil, "")
+
+	m.Delete(ctx, kept)
+	m.Delete(ctx, purged)
+
+	if page, _ := m.List(ctx, firstPage); len(page.Quotes) != 0 {
+		t.Errorf("List() with only deleted quotes returned %v", page.Quotes)
+	}
+
+	trash, err := m.ListDeleted(ctx)
+	if err != nil || len(trash) != 2 {
+		t.Fatalf("ListDeleted() = %v, %v, expected 2 quotes", trash, err)
+	}
+
+	if err := m.Restore(ctx, kept); err != nil {
+		t.Fatalf("Restore() unexpected error = %v", err)
+	}
+	if err := m.Restore(ctx, kept); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Restore() of restored quote error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+
+	count, err := m.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil || count != 1 {
+		t.Errorf("Purge() = %d, %v, expected 1 purged quote", count, err)
+	}
+
+	if _, err := m.Get(ctx, kept); err != nil {
+		t.Errorf("Get() of restored quote unexpected error = %v", err)
+	}
+	if err := m.Restore(ctx, purged); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("Restore() of purged quote error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+}
+
+func TestMemoryStorage_Pagination(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	for i := 0; i < 5; i++ {
+		m.Save(ctx, fmt.Sprintf("Quote %d", i), "Author", nil, "")
+	}
+	m.Delete(ctx, 2)
+
+	var ids []int
+	page := storage.Page{Limit: 2, WithTotal: true}
+	for {
+		result, err := m.List(ctx, page)
+		if err != nil {
+			t.Fatalf("List() unexpected error = %v", err)
+		}
+		if *result.Total != 4 {
+			t.Errorf("List() total = %d, expected 4", *result.Total)
+		}
+
+		for _, q := range result.Quotes {
+			ids = append(ids, q.Id)
+		}
+
+		if !result.HasMore {
+			break
+		}
+		page.AfterID = result.Quotes[len(result.Quotes)-1].Id
+	}
+
+	expected := []int{1, 3, 4, 5}
+	if len(ids) != len(expected) {
+		t.Fatalf("paginated ids = %v, expected %v", ids, expected)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("paginated ids = %v, expected %v", ids, expected)
+		}
+	}
+}
+
+func TestMemoryStorage_AuthorMatch(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	m.Save(ctx, "First quote", "Confucius", nil, "")
+	m.Save(ctx, "Second quote", "confucius", nil, "")
+	m.Save(ctx, "Third quote", "Confucius the Elder", nil, "")
+	m.Save(ctx, "Fourth quote", "Socrates", nil, "")
+
+	tests := []struct {
+		name   string
+		filter storage.AuthorFilter
+		ids    []int
+	}{
+		{
+			name:   "exact",
+			filter: storage.AuthorFilter{Name: "Confucius", Match: storage.MatchExact},
+			ids:    []int{1},
+		},
+		{
+			name:   "icase ranks exact case first",
+			filter: storage.AuthorFilter{Name: "confucius", Match: storage.MatchICase},
+			ids:    []int{2, 1},
+		},
+		{
+			name:   "prefix ranks shorter names first",
+			filter: storage.AuthorFilter{Name: "Conf", Match: storage.MatchPrefix},
+			ids:    []int{1, 2, 3},
+		},
+		{
+			name:   "fuzzy finds misspelled name",
+			filter: storage.AuthorFilter{Name: "Confucious", Match: storage.MatchFuzzy, Threshold: 0.3},
+			ids:    []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []int
+
+			// walk pages of one quote to exercise the ranked keyset
+			page := storage.Page{Limit: 1}
+			for {
+				result, err := m.ListByAuthor(ctx, tt.filter, page)
+				if err != nil {
+					t.Fatalf("ListByAuthor() unexpected error = %v", err)
+				}
+				for _, q := range result.Quotes {
+					ids = append(ids, q.Id)
+				}
+				if !result.HasMore {
+					break
+				}
+				page.AfterID = result.Quotes[0].Id
+				page.AfterRank = result.LastRank
+			}
+
+			if len(ids) != len(tt.ids) {
+				t.Fatalf("ListByAuthor() ids = %v, expected %v", ids, tt.ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.ids[i] {
+					t.Errorf("ListByAuthor() ids = %v, expected %v", ids, tt.ids)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStorage_Authors(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	first, _ := m.Save(ctx, "Life is simple", "Confucius", nil, "")
+	m.Save(ctx, "Know thyself", "Socrates", nil, "")
+	m.Save(ctx, "Real knowledge", "Confucius", nil, "")
+
+	page, err := m.ListAuthors(ctx, storage.Page{Limit: 10, WithTotal: true})
+	if err != nil {
+		t.Fatalf("ListAuthors() unexpected error = %v", err)
+	}
+	if *page.Total != 2 || page.Authors[0].Name != "Confucius" || page.Authors[0].QuotesCount != 2 {
+		t.Errorf("ListAuthors() returned %v, expected Confucius with 2 quotes first", page.Authors)
+	}
+
+	q, _ := m.Get(ctx, first)
+	quotes, err := m.ListByAuthorID(ctx, q.AuthorID, firstPage)
+	if err != nil || len(quotes.Quotes) != 2 {
+		t.Errorf("ListByAuthorID() = %v, %v, expected 2 quotes", quotes, err)
+	}
+
+	// authors without quotes stay resolvable but are not listed
+	m.Delete(ctx, 2)
+	if page, _ := m.ListAuthors(ctx, firstPage); len(page.Authors) != 1 {
+		t.Errorf("ListAuthors() returned %v, expected only Confucius", page.Authors)
+	}
+
+	if _, err := m.GetAuthor(ctx, 42); !errors.Is(err, storage.ErrAuthorNotFound) {
+		t.Errorf("GetAuthor() error = %v, expected %v", err, storage.ErrAuthorNotFound)
+	}
+}
+
+func TestMemoryStorage_Tags(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	m.Save(ctx, "Life is simple", "Confucius", []string{"life", "wisdom"}, "")
+	m.Save(ctx, "Know thyself", "Socrates", []string{"wisdom"}, "")
+	m.Save(ctx, "Premature optimization", "Knuth", nil, "")
+
+	anyTags, _ := m.ListByTags(ctx, storage.TagFilter{Tags: []string{"life", "wisdom"}, Match: storage.TagsAny}, firstPage)
+	allTags, _ := m.ListByTags(ctx, storage.TagFilter{Tags: []string{"life", "wisdom"}, Match: storage.TagsAll}, firstPage)
+	if len(anyTags.Quotes) != 2 || len(allTags.Quotes) != 1 || allTags.Quotes[0].Id != 1 {
+		t.Errorf("ListByTags() any = %v, all = %v, expected 2 and 1 quotes", anyTags.Quotes, allTags.Quotes)
+	}
+
+	q, err := m.AddTags(ctx, 3, []string{"programming"})
+	if err != nil || q.Version != 2 {
+		t.Fatalf("AddTags() = %v, %v, expected version 2", q, err)
+	}
+	if q, _ := m.AddTags(ctx, 3, []string{"programming"}); q.Version != 2 {
+		t.Errorf("AddTags() of present tag bumped version to %d", q.Version)
+	}
+
+	m.RemoveTags(ctx, 1, []string{"wisdom"})
+
+	tags, err := m.ListTags(ctx)
+	if err != nil || len(tags) != 3 || tags[0].Name != "life" || tags[0].QuotesCount != 1 {
+		t.Errorf("ListTags() = %v, %v, expected life, programming and wisdom once each", tags, err)
+	}
+
+	if _, err := m.RemoveTags(ctx, 42, []string{"life"}); !errors.Is(err, storage.ErrQuoteNotFound) {
+		t.Errorf("RemoveTags() error = %v, expected %v", err, storage.ErrQuoteNotFound)
+	}
+}
+
+func TestMemoryStorage_RandomFilter(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	m.Save(ctx, "Life is simple", "Confucius", []string{"life"}, "")
+	m.Save(ctx, "Life is really simple, but we insist on making it complicated", "Confucius", []string{"life"}, "")
+	m.Save(ctx, "Know thyself", "Socrates", []string{"wisdom"}, "")
+
+	for i := 0; i < 20; i++ {
+		q, err := m.Random(ctx, storage.RandomFilter{Author: "Confucius", Tag: "life", MaxLength: 20})
+		if err != nil || q.Text != "Life is simple" {
+			t.Fatalf("Random() = %v, %v, expected the short Confucius quote", q, err)
+		}
+	}
+
+	if _, err := m.Random(ctx, storage.RandomFilter{Author: "Socrates", Tag: "life"}); !errors.Is(err, storage.ErrQuotesListEmpty) {
+		t.Errorf("Random() error = %v, expected %v", err, storage.ErrQuotesListEmpty)
+	}
+}
+
+func TestMemoryStorage_Duplicates(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	id, _ := m.Save(ctx, "Life is simple, but we insist on making it complicated.", "Confucius", nil, "")
+
+	_, err := m.Save(ctx, "life is simple -- but we insist on making it complicated", "confucius", nil, "")
+	var dup *storage.DuplicateError
+	if !errors.As(err, &dup) || dup.ExistingID != id {
+		t.Fatalf("Save() of duplicate error = %v, expected duplicate of %d", err, id)
+	}
+
+	other, _ := m.Save(ctx, "Other quote", "Confucius", nil, "")
+	if _, err := m.Update(ctx, other, "LIFE IS SIMPLE, BUT WE INSIST ON MAKING IT COMPLICATED", "Confucius", 1); !errors.Is(err, storage.ErrDuplicateQuote) {
+		t.Errorf("Update() to duplicate error = %v, expected %v", err, storage.ErrDuplicateQuote)
+	}
+
+	// deleted quotes do not block saving, but their restore does
+	m.Delete(ctx, id)
+	again, err := m.Save(ctx, "Life is simple, but we insist on making it complicated.", "Confucius", nil, "")
+	if err != nil {
+		t.Fatalf("Save() after delete unexpected error = %v", err)
+	}
+	if err := m.Restore(ctx, id); !errors.As(err, &dup) || dup.ExistingID != again {
+		t.Errorf("Restore() of duplicate error = %v, expected duplicate of %d", err, again)
+	}
+}
+
+func TestMemoryStorage_Similar(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	id, _ := m.Save(ctx, "Life is simple, but we insist on making it complicated.", "Confucius", nil, "")
+	near, _ := m.Save(ctx, "Life is really simple, but we insist on making it complicated", "Confucius", nil, "")
+	m.Save(ctx, "The unexamined life is not worth living.", "Socrates", nil, "")
+
+	similar, err := m.Similar(ctx, "Life is simple, but we insist on making it complicated.", id, 0.5, 10)
+	if err != nil {
+		t.Fatalf("Similar() unexpected error = %v", err)
+	}
+	if len(similar) != 1 || similar[0].Id != near {
+		t.Fatalf("Similar() = %v, expected only quote %d", similar, near)
+	}
+	if similar[0].Score < 0.5 || similar[0].Score >= 1 {
+		t.Errorf("Similar() score = %.2f, expected in [0.5, 1)", similar[0].Score)
+	}
+}
+
+func TestMemoryStorage_Search(t *testing.T) {
+	ctx := context.Background()
+	m := New(slog.Default())
+
+	once, _ := m.Save(ctx, "Knowledge is power, and power corrupts", "Bacon", nil, "")
+	twice, _ := m.Save(ctx, "Power to the power", "Nobody", nil, "")
+	deleted, _ := m.Save(ctx, "Power of habit", "Duhigg", nil, "")
+	m.Save(ctx, "Знание — сила", "Бэкон", nil, "")
+	m.Save(ctx, "Real knowledge", "Confucius", nil, "")
+
+	if err := m.Delete(ctx, deleted); err != nil {
+		t.Fatalf("Delete() unexpected error = %v", err)
+	}
+
+	results, err := m.Search(ctx, "power", storage.SearchEnglish, 10)
+	if err != nil {
+		t.Fatalf("Search() unexpected error = %v", err)
+	}
+	if len(results) != 2 || results[0].Id != twice || results[1].Id != once {
+		t.Fatalf("Search() returned %v, expected ids %d, %d by rank", results, twice, once)
+	}
+	if results[0].Rank <= results[1].Rank {
+		t.Errorf("Search() ranks %v, %v, expected the denser match first", results[0].Rank, results[1].Rank)
+	}
+	if expected := "Knowledge is <b>power</b>, and <b>power</b> corrupts"; results[1].Headline != expected {
+		t.Errorf("Search() headline = %q, expected %q", results[1].Headline, expected)
+	}
+
+	// terms are prefixes and all of them must match, negated ones must not
+	if results, _ := m.Search(ctx, "know pow", storage.SearchEnglish, 10); len(results) != 1 || results[0].Id != once {
+		t.Errorf("Search() of two terms returned %v, expected id %d", results, once)
+	}
+	if results, _ := m.Search(ctx, "power -knowledge", storage.SearchEnglish, 10); len(results) != 1 || results[0].Id != twice {
+		t.Errorf("Search() with a negated term returned %v, expected id %d", results, twice)
+	}
+	if results, _ := m.Search(ctx, "power", storage.SearchEnglish, 1); len(results) != 1 || results[0].Id != twice {
+		t.Errorf("Search() with limit 1 returned %v, expected id %d", results, twice)
+	}
+
+	results, err = m.Search(ctx, "СИЛА", storage.SearchRussian, 10)
+	if err != nil || len(results) != 1 || results[0].Headline != "Знание — <b>сила</b>" {
+		t.Errorf("Search() in russian = %v, %v, expected the highlighted quote", results, err)
+	}
+
+	m.Save(ctx, `<img src=x onerror="alert(1)"> & alert`, "Mallory", nil, "")
+	results, err = m.Search(ctx, "alert", storage.SearchEnglish, 10)
+	if expected := `&lt;img src=x onerror=&#34;<b>alert</b>(1)&#34;&gt; &amp; <b>alert</b>`; err != nil || len(results) != 1 || results[0].Headline != expected {
+		t.Errorf("Search() of a quote with HTML = %v, %v, expected headline %q", results, err, expected)
+	}
+
+	if _, err := m.Search(ctx, "power", "de", 10); !errors.Is(err, storage.ErrFailedToSearchQuotes) {
+		t.Errorf("Search() with an unknown language error = %v, expected %v", err, storage.ErrFailedToSearchQuotes)
+	}
+	if results, err := m.Search(ctx, "-power", storage.SearchEnglish, 10); err != nil || len(results) != 0 {
+		t.Errorf("Search() of negated terms only = %v, %v, expected nothing", results, err)
+	}
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"app/internal/storage"
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Search is a simplified in-process counterpart of the postgres full-text search.
+// Every query term must prefix some word of the quote, terms starting with "-"
+// must not. There is no stemming, so lang only has to be supported.
+func (m *MemoryStorage) Search(ctx context.Context, query string, lang string, limit int) ([]*storage.SearchResult, error) {
+	if lang != storage.SearchEnglish && lang != storage.SearchRussian {
+		return nil, storage.ErrFailedToSearchQuotes
+	}
+
+	var include, exclude []string
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		negate := strings.HasPrefix(term, "-")
+
+		for _, word := range words(term) {
+			if negate {
+				exclude = append(exclude, word)
+			} else {
+				include = append(include, word)
+			}
+		}
+	}
+
+	if len(include) == 0 {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*storage.SearchResult
+
+	for _, q := range m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt == nil }) {
+		textWords := words(strings.ToLower(q.Text))
+
+		if !matchesAll(textWords, include) || matchesAny(textWords, exclude) {
+			continue
+		}
+
+		hits := 0
+		for _, w := range textWords {
+			if hasPrefixAny(w, include) {
+				hits++
+			}
+		}
+
+		results = append(results, &storage.SearchResult{
+			StorageQuote: *q,
+			Rank:         float64(hits) / float64(len(textWords)),
+			Headline:     headline(q.Text, include),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasPrefixAny(word string, terms []string) bool {
+	for _, t := range terms {
+		if strings.HasPrefix(word, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(textWords, terms []string) bool {
+	for _, t := range terms {
+		if !matchesAny(textWords, []string{t}) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(textWords, terms []string) bool {
+	for _, w := range textWords {
+		if hasPrefixAny(w, terms) {
+			return true
+		}
+	}
+	return false
+}
+
+// headline wraps words of text matching any term in <b></b>, like ts_headline
+// does, and escapes the rest, see storage.HTMLHeadline.
+func headline(text string, terms []string) string {
+	var b strings.Builder
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+			j++
+		}
+
+		word := string(runes[i:j])
+		if hasPrefixAny(strings.ToLower(word), terms) {
+			b.WriteString(storage.HeadlineStart + word + storage.HeadlineStop)
+		} else {
+			b.WriteString(word)
+		}
+		i = j
+	}
+
+	return storage.HTMLHeadline(b.String())
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"app/internal/lib/minhash"
+	"app/internal/storage"
+	"context"
+	"sort"
+)
+
+// Similar compares MinHash signatures of quote trigrams, which estimate the
+// pg_trgm similarity used by the postgres storage without comparing every
+// pair of trigram sets.
+func (m *MemoryStorage) Similar(ctx context.Context, text string, except int, threshold float64, limit int) ([]*storage.SimilarQuote, error) {
+	sig := minhash.Of(trigrams(text))
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*storage.SimilarQuote
+
+	for _, q := range m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt == nil && q.Id != except }) {
+		score := minhash.Similarity(sig, m.signatures[q.Id])
+		if score < threshold || score == 0 {
+			continue
+		}
+
+		results = append(results, &storage.SimilarQuote{
+			StorageQuote: *q,
+			Score:        score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"app/internal/domain/models"
+	"app/internal/storage"
+	"context"
+	"slices"
+	"sort"
+)
+
+func (m *MemoryStorage) ListByTags(ctx context.Context, tags storage.TagFilter, page storage.Page) (*storage.QuotesPage, error) {
+	var match func(q storage.StorageQuote) bool
+
+	switch tags.Match {
+	case storage.TagsAny:
+		match = func(q storage.StorageQuote) bool {
+			return slices.ContainsFunc(tags.Tags, func(t string) bool { return slices.Contains(q.Tags, t) })
+		}
+
+	case storage.TagsAll:
+		match = func(q storage.StorageQuote) bool {
+			return !slices.ContainsFunc(tags.Tags, func(t string) bool { return !slices.Contains(q.Tags, t) })
+		}
+
+	default:
+		return nil, storage.ErrFailedToListQuotes
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	quotes := m.collect(func(q storage.StorageQuote) bool { return q.DeletedAt == nil && match(q) })
+
+	return paginate(quotes, page), nil
+}
+
+func (m *MemoryStorage) AddTags(ctx context.Context, id int, tags []string) (*storage.StorageQuote, error) {
+	return m.changeTags(ctx, id, func(current []string) []string { return addTags(current, tags) })
+}
+
+func (m *MemoryStorage) RemoveTags(ctx context.Context, id int, tags []string) (*storage.StorageQuote, error) {
+	return m.changeTags(ctx, id, func(current []string) []string {
+		var kept []string
+		for _, t := range current {
+			if !slices.Contains(tags, t) {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	})
+}
+
+func (m *MemoryStorage) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, q := range m.quotes {
+		if q.DeletedAt != nil {
+			continue
+		}
+		for _, t := range q.Tags {
+			counts[t]++
+		}
+	}
+
+	tags := make([]*models.Tag, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, &models.Tag{Name: name, QuotesCount: count})
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].QuotesCount != tags[j].QuotesCount {
+			return tags[i].QuotesCount > tags[j].QuotesCount
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
+// changeTags replaces the tags of a not deleted quote with the result of change
+// and bumps its version when they differ.
+func (m *MemoryStorage) changeTags(ctx context.Context, id int, change func(current []string) []string) (*storage.StorageQuote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.alive(id)
+	if !ok {
+		m.log.WarnContext(ctx, "Quote not found", "id", id)
+		return nil, storage.ErrQuoteNotFound
+	}
+
+	tags := change(q.Tags)
+	if !slices.Equal(tags, q.Tags) {
+		q.Tags = tags
+		q.Version++
+		m.quotes[id] = q
+	}
+
+	m.log.DebugContext(ctx, "Quote tags changed successfully", "id", id, "tags", q.Tags, "version", q.Version)
+	return &q, nil
+}
+
+// addTags returns a new sorted slice with tags merged into current.
+func addTags(current []string, tags []string) []string {
+	merged := slices.Clone(current)
+	for _, t := range tags {
+		if !slices.Contains(merged, t) {
+			merged = append(merged, t)
+		}
+	}
+	slices.Sort(merged)
+
+	return merged
+}
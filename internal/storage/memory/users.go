@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"app/internal/storage"
+	"context"
+	"slices"
+	"time"
+)
+
+func (m *MemoryStorage) SaveUser(ctx context.Context, user *storage.User) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, u := range m.users {
+		if u.Name == user.Name {
+			return 0, storage.ErrUserExists
+		}
+	}
+
+	m.lastUserID++
+
+	u := *user
+	u.ID = m.lastUserID
+	u.CreatedAt = time.Now()
+	m.users[u.ID] = u
+
+	m.log.DebugContext(ctx, "User saved", "id", u.ID, "name", u.Name, "role", u.Role)
+	return u.ID, nil
+}
+
+func (m *MemoryStorage) GetUser(ctx context.Context, id int) (*storage.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.users[id]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return &u, nil
+}
+
+func (m *MemoryStorage) GetUserByName(ctx context.Context, name string) (*storage.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, u := range m.users {
+		if u.Name == name {
+			return &u, nil
+		}
+	}
+
+	return nil, storage.ErrUserNotFound
+}
+
+func (m *MemoryStorage) ListUsers(ctx context.Context) ([]*storage.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := make([]*storage.User, 0, len(m.users))
+	for _, u := range m.users {
+		users = append(users, &u)
+	}
+
+	slices.SortFunc(users, func(a, b *storage.User) int { return a.ID - b.ID })
+
+	return users, nil
+}
+
+func (m *MemoryStorage) SetUserRole(ctx context.Context, id int, role string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.users[id]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+
+	u.Role = role
+	m.users[id] = u
+
+	m.log.DebugContext(ctx, "User role changed", "id", id, "role", role)
+	return nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, hash, scopes, created_at, expires_at, revoked_at"
+
+func scanAPIKey(row pgx.Row, k *storage.APIKey) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, &k.Scopes, &k.CreatedAt, &k.ExpiresAt, &k.RevokedAt)
+}
+
+func (p *PostgreStorage) SaveAPIKey(ctx context.Context, key *storage.APIKey) (int, error) {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	var id int
+	if err := p.conn.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.Hash, key.Scopes, key.ExpiresAt).Scan(&id); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveAPIKey.Error(), "error", err, "name", key.Name)
+		return 0, fmt.Errorf("%w: %w", storage.ErrFailedToSaveAPIKey, err)
+	}
+
+	p.log.DebugContext(ctx, "API key saved", "id", id, "name", key.Name, "scopes", key.Scopes)
+	return id, nil
+}
+
+func (p *PostgreStorage) APIKeyByHash(ctx context.Context, hash string) (*storage.APIKey, error) {
+	var k storage.APIKey
+	if err := scanAPIKey(p.conn.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE hash = $1", hash), &k); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrAPIKeyNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetAPIKey.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetAPIKey, err)
+	}
+
+	return &k, nil
+}
+
+func (p *PostgreStorage) ListAPIKeys(ctx context.Context) ([]*storage.APIKey, error) {
+	rows, err := p.conn.Query(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY id")
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListAPIKeys.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListAPIKeys, err)
+	}
+	defer rows.Close()
+
+	var keys []*storage.APIKey
+	for rows.Next() {
+		var k storage.APIKey
+		if err := scanAPIKey(rows, &k); err != nil {
+			p.log.ErrorContext(ctx, storage.ErrFailedToListAPIKeys.Error(), "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListAPIKeys, err)
+		}
+		keys = append(keys, &k)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListAPIKeys.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListAPIKeys, err)
+	}
+
+	return keys, nil
+}
+
+func (p *PostgreStorage) RevokeAPIKey(ctx context.Context, id int) error {
+	result, err := p.conn.Exec(ctx, "UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL", id)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveAPIKey.Error(), "error", err, "id", id)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToSaveAPIKey, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	p.log.DebugContext(ctx, "API key revoked", "id", id)
+	return nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"app/internal/domain/models"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// authorID returns the id of the author with the given name, creating it when needed.
+// Names are unique after the whitespace normalization done by the service.
+func (p *PostgreStorage) authorID(ctx context.Context, q querier, name string) (int, error) {
+	query := `
+		INSERT INTO authors (name) VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = authors.name
+		RETURNING id`
+
+	var id int
+	if err := q.QueryRow(ctx, query, name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to resolve author %q: %w", name, err)
+	}
+
+	return id, nil
+}
+
+func (p *PostgreStorage) ListAuthors(ctx context.Context, page storage.Page) (*storage.AuthorsPage, error) {
+
+	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT a.id, a.name, COUNT(q.id)
+		FROM authors a JOIN quotes q ON q.author_id = a.id AND NOT q.is_deleted
+		WHERE a.id > $1
+		GROUP BY a.id
+		ORDER BY a.id
+		LIMIT $2`
+
+	rows, err := tx.Query(ctx, query, page.AfterID, page.Limit+1)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListAuthors.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListAuthors, err)
+	}
+	defer rows.Close()
+
+	result := &storage.AuthorsPage{}
+
+	for rows.Next() {
+		var a models.Author
+		if err := rows.Scan(&a.ID, &a.Name, &a.QuotesCount); err != nil {
+			p.log.ErrorContext(ctx, "Failed to scan author", "error", err)
+			return nil, fmt.Errorf("failed to scan author: %w", err)
+		}
+		result.Authors = append(result.Authors, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListAuthors.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListAuthors, err)
+	}
+
+	if len(result.Authors) > page.Limit {
+		result.Authors = result.Authors[:page.Limit]
+		result.HasMore = true
+	}
+
+	if page.WithTotal {
+		var total int
+		err := tx.QueryRow(ctx, "SELECT COUNT(DISTINCT author_id) FROM quotes WHERE NOT is_deleted").Scan(&total)
+		if err != nil {
+			p.log.ErrorContext(ctx, "Failed to count authors", "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListAuthors, err)
+		}
+		result.Total = &total
+	}
+
+	tx.Commit(ctx)
+
+	return result, nil
+}
+
+func (p *PostgreStorage) GetAuthor(ctx context.Context, id int) (*models.Author, error) {
+
+	query := `
+		SELECT a.id, a.name, COUNT(q.id)
+		FROM authors a LEFT JOIN quotes q ON q.author_id = a.id AND NOT q.is_deleted
+		WHERE a.id = $1
+		GROUP BY a.id`
+
+	var a models.Author
+	err := p.conn.QueryRow(ctx, query, id).Scan(&a.ID, &a.Name, &a.QuotesCount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.WarnContext(ctx, "Author not found", "id", id)
+			return nil, storage.ErrAuthorNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetAuthor.Error(), "error", err, "id", id)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetAuthor, err)
+	}
+
+	return &a, nil
+}
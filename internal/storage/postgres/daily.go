@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *PostgreStorage) Pick(ctx context.Context, pick func(count int) int) (*storage.StorageQuote, error) {
+
+	// the count and the offset are read from one snapshot
+	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var count int
+	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM quotes WHERE NOT is_deleted").Scan(&count); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToPickQuote.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToPickQuote, err)
+	}
+
+	if count == 0 {
+		return nil, storage.ErrQuotesListEmpty
+	}
+
+	query := "SELECT " + quoteColumns + " FROM " + quotesJoin + " WHERE NOT q.is_deleted ORDER BY q.id OFFSET $1 LIMIT 1"
+
+	var q storage.StorageQuote
+	if err := scanQuote(tx.QueryRow(ctx, query, pick(count)), &q); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToPickQuote.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToPickQuote, err)
+	}
+
+	tx.Commit(ctx)
+
+	return &q, nil
+}
+
+func (p *PostgreStorage) PinDaily(ctx context.Context, day time.Time, quoteID int) error {
+	query := `
+		INSERT INTO daily_pins (day, quote_id)
+		SELECT $1::date, id FROM quotes WHERE id = $2 AND NOT is_deleted
+		ON CONFLICT (day) DO UPDATE SET quote_id = EXCLUDED.quote_id`
+
+	result, err := p.conn.Exec(ctx, query, day.Format(time.DateOnly), quoteID)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToPinQuote.Error(), "error", err, "day", day, "id", quoteID)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToPinQuote, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		p.log.WarnContext(ctx, "Quote not found", "id", quoteID)
+		return storage.ErrQuoteNotFound
+	}
+
+	p.log.DebugContext(ctx, "Daily quote pinned", "day", day.Format(time.DateOnly), "id", quoteID)
+	return nil
+}
+
+func (p *PostgreStorage) UnpinDaily(ctx context.Context, day time.Time) error {
+	result, err := p.conn.Exec(ctx, "DELETE FROM daily_pins WHERE day = $1::date", day.Format(time.DateOnly))
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToPinQuote.Error(), "error", err, "day", day)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToPinQuote, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return storage.ErrPinNotFound
+	}
+
+	p.log.DebugContext(ctx, "Daily quote unpinned", "day", day.Format(time.DateOnly))
+	return nil
+}
+
+func (p *PostgreStorage) DailyPin(ctx context.Context, day time.Time) (*storage.StorageQuote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM daily_pins d
+		JOIN quotes q ON q.id = d.quote_id
+		JOIN authors a ON a.id = q.author_id
+		WHERE d.day = $1::date AND NOT q.is_deleted`
+
+	var q storage.StorageQuote
+	if err := scanQuote(p.conn.QueryRow(ctx, query, day.Format(time.DateOnly)), &q); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrPinNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetQuote.Error(), "error", err, "day", day)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
+	}
+
+	return &q, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fingerprintIndex is the partial unique index on fingerprints of not deleted quotes.
+const fingerprintIndex = "quotes_fingerprint_uindex"
+
+func (p *PostgreStorage) FindByFingerprints(ctx context.Context, fingerprints []string) (map[string]int, error) {
+	ids, err := findByFingerprints(ctx, p.conn, fingerprints)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetQuote.Error(), "error", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// findByFingerprints is FindByFingerprints within a transaction or not.
+func findByFingerprints(ctx context.Context, q querier, fingerprints []string) (map[string]int, error) {
+	query := "SELECT fingerprint, id FROM quotes WHERE fingerprint = ANY($1::text[]) AND NOT is_deleted"
+
+	rows, err := q.Query(ctx, query, fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			fp string
+			id int
+		)
+		if err := rows.Scan(&fp, &id); err != nil {
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
+		}
+		ids[fp] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
+	}
+
+	return ids, nil
+}
+
+// MergeDuplicates keeps the quote keepID and moves the quotes duplicateIDs to
+// the trash. Their tags and daily pins are moved to the kept quote, which gets
+// the fingerprint. It is used by cmd/dedupe.
+func (p *PostgreStorage) MergeDuplicates(ctx context.Context, keepID int, duplicateIDs []int, fingerprint string) error {
+
+	tx, err := p.conn.Begin(ctx)
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := []struct {
+		sql  string
+		args []any
+	}{
+		{
+			sql:  "INSERT INTO quote_tags (quote_id, tag_id) SELECT $1, tag_id FROM quote_tags WHERE quote_id = ANY($2) ON CONFLICT DO NOTHING",
+			args: []any{keepID, duplicateIDs},
+		},
+		{
+			sql:  "UPDATE daily_pins SET quote_id = $1 WHERE quote_id = ANY($2)",
+			args: []any{keepID, duplicateIDs},
+		},
+		{
+			sql:  "UPDATE quotes SET is_deleted = TRUE, deleted_at = NOW(), fingerprint = $2 WHERE id = ANY($1) AND NOT is_deleted",
+			args: []any{duplicateIDs, fingerprint},
+		},
+		{
+			sql:  "UPDATE quotes SET fingerprint = $2, version = version + 1 WHERE id = $1",
+			args: []any{keepID, fingerprint},
+		},
+	}
+
+	for _, q := range queries {
+		if _, err := tx.Exec(ctx, q.sql, q.args...); err != nil {
+			p.log.ErrorContext(ctx, "Failed to merge duplicates", "error", err, "id", keepID)
+			return fmt.Errorf("failed to merge duplicates of quote %d: %w", keepID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.log.ErrorContext(ctx, ErrTxCommit.Error(), "err", err.Error())
+
+		return fmt.Errorf("%w:%w", ErrTxCommit, err)
+	}
+
+	p.log.DebugContext(ctx, "Duplicates merged", "id", keepID, "duplicates", duplicateIDs)
+	return nil
+}
+
+// SetFingerprints fills fingerprints of quotes by id. It is used by cmd/dedupe
+// for quotes stored before fingerprints existed.
+func (p *PostgreStorage) SetFingerprints(ctx context.Context, fingerprints map[int]string) error {
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(fingerprints))
+	fps := make([]string, 0, len(fingerprints))
+	for id, fp := range fingerprints {
+		ids = append(ids, id)
+		fps = append(fps, fp)
+	}
+
+	query := `
+		UPDATE quotes q SET fingerprint = f.fingerprint
+		FROM unnest($1::int[], $2::text[]) AS f(id, fingerprint)
+		WHERE q.id = f.id AND q.fingerprint IS DISTINCT FROM f.fingerprint`
+
+	if _, err := p.conn.Exec(ctx, query, ids, fps); err != nil {
+		p.log.ErrorContext(ctx, "Failed to set fingerprints", "error", err)
+		return fmt.Errorf("failed to set fingerprints: %w", err)
+	}
+
+	return nil
+}
+
+// duplicateOf returns a *storage.DuplicateError pointing at the not deleted
+// quote with the fingerprint.
+func (p *PostgreStorage) duplicateOf(ctx context.Context, fingerprint string) error {
+	var id int
+
+	err := p.conn.QueryRow(ctx, "SELECT id FROM quotes WHERE fingerprint = $1 AND NOT is_deleted", fingerprint).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the duplicate was deleted in the meantime
+			return fmt.Errorf("%w: %w", storage.ErrFailedToSaveQuote, storage.ErrDuplicateQuote)
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetQuote.Error(), "error", err)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
+	}
+
+	p.log.InfoContext(ctx, "Duplicate quote", "existingID", id)
+	return &storage.DuplicateError{ExistingID: id}
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == fingerprintIndex
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// exportFetchSize is the number of rows read from the export cursor at once.
+const exportFetchSize = 500
+
+// Export reads quotes through a server side cursor, so only one fetch of rows
+// is held in memory however large the collection is.
+func (p *PostgreStorage) Export(ctx context.Context, filter storage.ExportFilter, fn func(q *storage.StorageQuote) error) error {
+	var (
+		q   listQuery
+		err error
+	)
+
+	switch {
+	case filter.Author != nil:
+		q, err = authorQuery(*filter.Author)
+	case filter.Tags != nil:
+		q, err = tagsQuery(*filter.Tags)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the cursor lives until the end of the transaction
+	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if q.threshold > 0 {
+		_, err := tx.Exec(ctx, "SELECT set_config('pg_trgm.similarity_threshold', $1, true)", strconv.FormatFloat(q.threshold, 'f', -1, 64))
+		if err != nil {
+			p.log.ErrorContext(ctx, "Failed to set similarity threshold", "error", err)
+			return fmt.Errorf("%w: %w", storage.ErrFailedToExportQuotes, err)
+		}
+	}
+
+	where := "NOT q.is_deleted"
+	if q.where != "" {
+		where += " AND " + q.where
+	}
+
+	declare := "DECLARE export_cursor NO SCROLL CURSOR FOR SELECT " + quoteColumns + " FROM " + quotesJoin + " WHERE " + where + " ORDER BY q.id"
+
+	// DECLARE can't be prepared with parameters, pgx interpolates them instead
+	if _, err := tx.Exec(ctx, declare, append([]any{pgx.QueryExecModeSimpleProtocol}, q.args...)...); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToExportQuotes.Error(), "error", err)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToExportQuotes, err)
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM export_cursor", exportFetchSize)
+	exported := 0
+
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			p.log.ErrorContext(ctx, storage.ErrFailedToExportQuotes.Error(), "error", err)
+			return fmt.Errorf("%w: %w", storage.ErrFailedToExportQuotes, err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			var quote storage.StorageQuote
+			if err := scanQuote(rows, &quote); err != nil {
+				rows.Close()
+				p.log.ErrorContext(ctx, storage.ErrFailedToExportQuotes.Error(), "error", err)
+				return fmt.Errorf("%w: %w", storage.ErrFailedToExportQuotes, err)
+			}
+			fetched++
+
+			if err := fn(&quote); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			p.log.ErrorContext(ctx, storage.ErrFailedToExportQuotes.Error(), "error", err)
+			return fmt.Errorf("%w: %w", storage.ErrFailedToExportQuotes, err)
+		}
+
+		exported += fetched
+		if fetched < exportFetchSize {
+			break
+		}
+	}
+
+	tx.Commit(ctx)
+
+	p.log.DebugContext(ctx, "Quotes exported successfully", "count", exported)
+	return nil
+}
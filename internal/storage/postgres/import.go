@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"app/internal/domain/models"
+	"app/internal/lib/fingerprint"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// importBatchSize is the number of quotes sent with one COPY.
+const importBatchSize = 1000
+
+func (p *PostgreStorage) Import(ctx context.Context, quotes []*models.Quote, owner string, atomic bool) ([]int, error) {
+	ids := make([]int, len(quotes))
+
+	if atomic {
+		duplicates, err := p.importBatches(ctx, quotes, ids, owner, true)
+		if err != nil {
+			p.log.ErrorContext(ctx, storage.ErrFailedToImportQuotes.Error(), "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToImportQuotes, err)
+		}
+		if len(duplicates) > 0 {
+			p.log.InfoContext(ctx, "Imported quotes already exist", "count", len(duplicates))
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToImportQuotes, &storage.ImportDuplicatesError{Duplicates: duplicates})
+		}
+
+		p.log.DebugContext(ctx, "Quotes imported successfully", "count", len(ids))
+		return ids, nil
+	}
+
+	var errs []error
+	duplicates := make(map[int]*storage.DuplicateError)
+	for start := 0; start < len(quotes); start += importBatchSize {
+		end := min(start+importBatchSize, len(quotes))
+
+		batchDuplicates, err := p.importBatches(ctx, quotes[start:end], ids[start:end], owner, false)
+		if err != nil {
+			clear(ids[start:end])
+			errs = append(errs, fmt.Errorf("quotes %d-%d: %w", start+1, end, err))
+			continue
+		}
+		for i, dup := range batchDuplicates {
+			duplicates[start+i] = dup
+		}
+	}
+
+	if len(duplicates) > 0 {
+		p.log.InfoContext(ctx, "Imported quotes already exist", "count", len(duplicates))
+		errs = append(errs, &storage.ImportDuplicatesError{Duplicates: duplicates})
+	}
+
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+		p.log.ErrorContext(ctx, storage.ErrFailedToImportQuotes.Error(), "error", err)
+		return ids, fmt.Errorf("%w: %w", storage.ErrFailedToImportQuotes, err)
+	}
+
+	p.log.DebugContext(ctx, "Quotes imported successfully", "count", len(ids))
+	return ids, nil
+}
+
+// importBatches inserts quotes in one transaction, batch by batch, fills ids
+// and returns the quotes skipped as duplicates by index. Atomic imports stop
+// at the first duplicate and commit nothing.
+func (p *PostgreStorage) importBatches(ctx context.Context, quotes []*models.Quote, ids []int, owner string, atomic bool) (map[int]*storage.DuplicateError, error) {
+
+	tx, err := p.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// dropped with the transaction, the pool may hand the connection to anyone
+	_, err = tx.Exec(ctx, `
+		CREATE TEMP TABLE import_quotes (
+			id INT, quote TEXT, author_id INT, fingerprint TEXT, owner TEXT
+		) ON COMMIT DROP`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import table: %w", err)
+	}
+
+	duplicates := make(map[int]*storage.DuplicateError)
+	for start := 0; start < len(quotes); start += importBatchSize {
+		end := min(start+importBatchSize, len(quotes))
+
+		batchDuplicates, err := p.importBatch(ctx, tx, quotes[start:end], ids[start:end], owner)
+		if err != nil {
+			return nil, err
+		}
+		for i, dup := range batchDuplicates {
+			duplicates[start+i] = dup
+		}
+
+		if atomic && len(duplicates) > 0 {
+			return duplicates, nil
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrTxCommit, err)
+	}
+
+	return duplicates, nil
+}
+
+// importBatch copies one batch of quotes into the import table and moves
+// them to quotes, skipping quotes another request stored since the service
+// looked for duplicates. Ids are taken from the quotes sequence up front so
+// that COPY, which returns nothing, can also fill quote_tags.
+func (p *PostgreStorage) importBatch(ctx context.Context, tx pgx.Tx, quotes []*models.Quote, ids []int, owner string) (map[int]*storage.DuplicateError, error) {
+	var authors, tags []string
+	for _, q := range quotes {
+		authors = append(authors, q.Author)
+		tags = append(tags, q.Tags...)
+	}
+
+	authorIDs, err := upsertNames(ctx, tx, AuthorTable, authors)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs, err := upsertNames(ctx, tx, "tags", tags)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, "SELECT nextval(pg_get_serial_sequence('quotes', 'id')) FROM generate_series(1, $1)", len(quotes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate quote ids: %w", err)
+	}
+
+	// quotes of unknown submitters have no owner
+	var ownerOrNull any
+	if owner != "" {
+		ownerOrNull = owner
+	}
+
+	allocated, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate quote ids: %w", err)
+	}
+	copy(ids, allocated)
+
+	fps := make([]string, len(quotes))
+	for i, q := range quotes {
+		fps[i] = fingerprint.Of(q.Text, q.Author)
+	}
+
+	if _, err := tx.Exec(ctx, "TRUNCATE import_quotes"); err != nil {
+		return nil, fmt.Errorf("failed to clear import table: %w", err)
+	}
+
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"import_quotes"},
+		[]string{IdColumn, quoteColumn, authorIdColumn, fingerprintColumn, ownerColumn},
+		pgx.CopyFromSlice(len(quotes), func(i int) ([]any, error) {
+			q := quotes[i]
+			return []any{ids[i], q.Text, authorIDs[q.Author], fps[i], ownerOrNull}, nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy quotes: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s, %[3]s, %[4]s, %[5]s, %[6]s)
+		SELECT %[2]s, %[3]s, %[4]s, %[5]s, %[6]s FROM import_quotes ORDER BY %[2]s
+		ON CONFLICT (%[5]s) WHERE NOT is_deleted DO NOTHING
+		RETURNING %[2]s`,
+		QuoteTable, IdColumn, quoteColumn, authorIdColumn, fingerprintColumn, ownerColumn,
+	)
+
+	rows, err = tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert quotes: %w", err)
+	}
+
+	inserted, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert quotes: %w", err)
+	}
+
+	duplicates := make(map[int]*storage.DuplicateError)
+	if len(inserted) < len(quotes) {
+		stored := make(map[int]bool, len(inserted))
+		for _, id := range inserted {
+			stored[id] = true
+		}
+
+		var skipped []string
+		for i := range quotes {
+			if !stored[ids[i]] {
+				skipped = append(skipped, fps[i])
+			}
+		}
+
+		existing, err := findByFingerprints(ctx, tx, skipped)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range quotes {
+			if !stored[ids[i]] {
+				duplicates[i] = &storage.DuplicateError{ExistingID: existing[fps[i]]}
+				ids[i] = 0
+			}
+		}
+	}
+
+	var quoteTags [][]any
+	for i, q := range quotes {
+		if ids[i] == 0 {
+			continue
+		}
+		for _, t := range q.Tags {
+			quoteTags = append(quoteTags, []any{ids[i], tagIDs[t]})
+		}
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"quote_tags"}, []string{"quote_id", "tag_id"}, pgx.CopyFromRows(quoteTags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy quote tags: %w", err)
+	}
+
+	return duplicates, nil
+}
+
+// upsertNames makes sure every name exists in table, authors or tags, and
+// returns their ids by name.
+func upsertNames(ctx context.Context, tx pgx.Tx, table string, names []string) (map[string]int, error) {
+	ids := make(map[string]int)
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (name) SELECT DISTINCT unnest($1::text[])
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id, name`, table)
+
+	rows, err := tx.Query(ctx, query, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", table, err)
+		}
+		ids[name] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", table, err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *PostgreStorage) List(ctx context.Context, page storage.Page) (*storage.QuotesPage, error) {
+	return p.listPage(ctx, listQuery{}, page)
+}
+
+func (p *PostgreStorage) ListByAuthor(ctx context.Context, author storage.AuthorFilter, page storage.Page) (*storage.QuotesPage, error) {
+	q, err := authorQuery(author)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.listPage(ctx, q, page)
+}
+
+// authorQuery builds the filter of quotes by author name.
+func authorQuery(author storage.AuthorFilter) (listQuery, error) {
+	var q listQuery
+
+	switch author.Match {
+	case storage.MatchExact:
+		q.where = "a.name = $1::text"
+		q.args = []any{author.Name}
+
+	case storage.MatchICase:
+		q.where = "lower(a.name) = lower($1::text)"
+		q.rank = "CASE WHEN a.name = $1::text THEN 1.0 ELSE 0.5 END"
+		q.args = []any{author.Name}
+
+	case storage.MatchPrefix:
+		q.where = `lower(a.name) LIKE $2::text ESCAPE '\'`
+		q.rank = "char_length($1::text)::float8 / char_length(a.name)"
+		q.args = []any{author.Name, likePrefix(strings.ToLower(author.Name))}
+
+	case storage.MatchFuzzy:
+		// % uses the trigram index and pg_trgm.similarity_threshold
+		q.where = "lower(a.name) % lower($1::text)"
+		q.rank = "similarity(lower(a.name), lower($1::text))"
+		q.args = []any{author.Name}
+		q.threshold = author.Threshold
+
+	default:
+		return q, fmt.Errorf("%w: unknown match mode %q", storage.ErrFailedToListByAuthor, author.Match)
+	}
+
+	return q, nil
+}
+
+func (p *PostgreStorage) ListByAuthorID(ctx context.Context, authorID int, page storage.Page) (*storage.QuotesPage, error) {
+	return p.listPage(ctx, listQuery{where: "q.author_id = $1", args: []any{authorID}}, page)
+}
+
+// listQuery is a filter of a quotes list.
+type listQuery struct {
+	where     string // extra condition, placeholders are numbered from $1 and match args
+	rank      string // match quality expression, higher is better; empty means order by id
+	args      []any
+	threshold float64 // pg_trgm similarity threshold for the % operator
+}
+
+// listPage reads one keyset page of not deleted quotes matching the query.
+func (p *PostgreStorage) listPage(ctx context.Context, q listQuery, page storage.Page) (*storage.QuotesPage, error) {
+
+	// the page and the total are read from one snapshot
+	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if q.threshold > 0 {
+		_, err := tx.Exec(ctx, "SELECT set_config('pg_trgm.similarity_threshold', $1, true)", strconv.FormatFloat(q.threshold, 'f', -1, 64))
+		if err != nil {
+			p.log.ErrorContext(ctx, "Failed to set similarity threshold", "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListQuotes, err)
+		}
+	}
+
+	where := "NOT q.is_deleted"
+	if q.where != "" {
+		where += " AND " + q.where
+	}
+
+	args := append([]any{}, q.args...)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	rank := q.rank
+	if rank == "" {
+		rank = "1.0"
+	}
+
+	keyset := "q.id > " + arg(page.AfterID)
+	order := "q.id"
+	if q.rank != "" {
+		order = "rank DESC, q.id"
+		if page.AfterID > 0 {
+			r := arg(page.AfterRank)
+			keyset = fmt.Sprintf("((%s)::float8 < %s OR ((%s)::float8 = %s AND %s))", rank, r, rank, r, keyset)
+		} else {
+			keyset = "TRUE"
+		}
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s, (%s)::float8 AS rank FROM %s WHERE %s AND %s ORDER BY %s LIMIT %s",
+		quoteColumns,
+		rank,
+		quotesJoin,
+		where,
+		keyset,
+		order,
+		// one extra row tells whether there is a next page
+		arg(page.Limit+1),
+	)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		p.log.ErrorContext(ctx, "Failed to query quotes", "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListQuotes, err)
+	}
+	defer rows.Close()
+
+	result := &storage.QuotesPage{}
+	var ranks []float64
+
+	for rows.Next() {
+		var (
+			quote storage.StorageQuote
+			r     float64
+		)
+		if err := scanQuote(rows, &quote, &r); err != nil {
+			p.log.ErrorContext(ctx, "Failed to scan quote", "error", err)
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		result.Quotes = append(result.Quotes, &quote)
+		ranks = append(ranks, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, "Failed to read quotes", "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListQuotes, err)
+	}
+
+	if len(result.Quotes) > page.Limit {
+		result.Quotes = result.Quotes[:page.Limit]
+		result.HasMore = true
+	}
+	if n := len(result.Quotes); n > 0 {
+		result.LastRank = ranks[n-1]
+	}
+
+	if page.WithTotal {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quotesJoin, where)
+
+		var total int
+		if err := tx.QueryRow(ctx, countQuery, q.args...).Scan(&total); err != nil {
+			p.log.ErrorContext(ctx, "Failed to count quotes", "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListQuotes, err)
+		}
+		result.Total = &total
+	}
+
+	tx.Commit(ctx)
+
+	return result, nil
+}
+
+// likePrefix escapes LIKE wildcards in s and appends %.
+func likePrefix(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	return r.Replace(s) + "%"
+}
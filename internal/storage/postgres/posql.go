@@ -1,28 +1,49 @@
 package postgres
 
 import (
-	"app/internal/domain/models"
+	"app/internal/lib/fingerprint"
 	"app/internal/storage"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const (
-	QuoteTable      = "quotes"
-	IdColumn        = "id"
-	quoteColumn     = "quote"
-	authorColumn    = "author"
-	isDeletedColumn = "is_deleted"
+	QuoteTable        = "quotes"
+	AuthorTable       = "authors"
+	IdColumn          = "id"
+	quoteColumn       = "quote"
+	authorIdColumn    = "author_id"
+	nameColumn        = "name"
+	versionColumn     = "version"
+	isDeletedColumn   = "is_deleted"
+	deletedAtColumn   = "deleted_at"
+	fingerprintColumn = "fingerprint"
+	ownerColumn       = "owner"
+)
+
+// Quotes are always read joined with their authors. quoteColumns is the
+// select list expected by scanQuote, quotes are aliased q and authors a.
+const (
+	quoteColumns = "q.id, q.quote, a.name, " + tagsColumn + ", q.author_id, q.version, COALESCE(q.owner, '')"
+	quotesJoin   = "quotes q JOIN authors a ON a.id = q.author_id"
+
+	// tagsColumn is the sorted array of tag names of the quote q
+	tagsColumn = "ARRAY(SELECT t.name FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE qt.quote_id = q.id ORDER BY t.name)"
 )
 
 type PostgreStorage struct {
 	conn *pgxpool.Pool
 	log  *slog.Logger
+
+	lastLimitSweep atomic.Int64 // unix nanoseconds, see TakeToken
 }
 
 var (
@@ -31,6 +52,13 @@ var (
 	ErrQuery    = errors.New("can't do query")
 )
 
+// querier is implemented by both the pool and transactions.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 func New(ctx context.Context, log *slog.Logger, connString string) (*PostgreStorage, error) {
 	log.Debug("Connecting to database", "Connect String", connString)
 
@@ -56,178 +84,254 @@ func New(ctx context.Context, log *slog.Logger, connString string) (*PostgreStor
 	}, nil
 }
 
-func (p *PostgreStorage) Save(ctx context.Context, quote string, author string) (int, error) {
+func (p *PostgreStorage) Save(ctx context.Context, quote string, author string, tags []string, owner string) (int, error) {
+
+	tx, err := p.conn.Begin(ctx)
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return 0, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	authorID, err := p.authorID(ctx, tx, author)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveQuote.Error(), "error", err)
+
+		return 0, fmt.Errorf("%w: %w", storage.ErrFailedToSaveQuote, err)
+	}
 
 	query := fmt.Sprintf(
-		"INSERT INTO %s (%s, %s) VALUES ($1,$2) RETURNING %s",
+		"INSERT INTO %s (%s, %s, %s, %s) VALUES ($1,$2,$3,NULLIF($4,'')) ON CONFLICT (%s) WHERE NOT %s DO NOTHING RETURNING %s",
 		QuoteTable,
 		quoteColumn,
-		authorColumn,
+		authorIdColumn,
+		fingerprintColumn,
+		ownerColumn,
+		fingerprintColumn,
+		isDeletedColumn,
 		IdColumn,
 	)
 
 	var id int
 
-	err := p.conn.QueryRow(ctx, query, quote, author).Scan(&id)
+	fp := fingerprint.Of(quote, author)
+
+	err = tx.QueryRow(ctx, query, quote, authorID, fp, owner).Scan(&id)
 	if err != nil {
-		p.log.Error(storage.ErrFailedToSaveQuote.Error(), "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, p.duplicateOf(ctx, fp)
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveQuote.Error(), "error", err)
+
+		return 0, fmt.Errorf("%w: %w", storage.ErrFailedToSaveQuote, err)
+	}
+
+	if _, err := p.tagQuote(ctx, tx, id, tags); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveQuote.Error(), "error", err)
 
 		return 0, fmt.Errorf("%w: %w", storage.ErrFailedToSaveQuote, err)
 	}
 
-	p.log.Debug("Quote saved successfully", "id", id, "quote", quote, "author", author)
+	if err := tx.Commit(ctx); err != nil {
+		p.log.ErrorContext(ctx, ErrTxCommit.Error(), "err", err.Error())
+
+		return 0, fmt.Errorf("%w:%w", ErrTxCommit, err)
+	}
+
+	p.log.DebugContext(ctx, "Quote saved successfully", "id", id, "quote", quote, "author", author, "tags", tags)
 	return id, nil
 
 }
 
 func (p *PostgreStorage) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM quotes WHERE id = $1;`
+	query := `UPDATE quotes SET is_deleted = TRUE, deleted_at = NOW() WHERE id = $1 AND NOT is_deleted;`
 
 	result, err := p.conn.Exec(ctx, query, id)
 	if err != nil {
-		p.log.Error("Failed to delete quote", "error", err, "id", id)
+		p.log.ErrorContext(ctx, "Failed to delete quote", "error", err, "id", id)
 		return fmt.Errorf("failed to delete quote: %w", err)
 	}
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		p.log.Warn("Quote not found", "id", id)
+		p.log.WarnContext(ctx, "Quote not found", "id", id)
 		return storage.ErrQuoteNotFound // Ваша кастомная ошибка (например, "quote not found")
 	}
 
-	p.log.Debug("Quote deleted successfully", "id", id)
+	p.log.DebugContext(ctx, "Quote deleted successfully", "id", id)
 	return nil
 }
 
-func (p *PostgreStorage) Get(ctx context.Context, id int) (*models.Quote, error) {
-	return nil, nil
-}
-
-func (p *PostgreStorage) List(ctx context.Context) ([]*storage.StorageQuote, error) {
+func (p *PostgreStorage) Update(ctx context.Context, id int, quote string, author string, version int) (*storage.StorageQuote, error) {
 
-	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel: pgx.RepeatableRead,
-	})
+	tx, err := p.conn.Begin(ctx)
 	if err != nil {
-		p.log.Error(ErrTxBegin.Error(), "err", err.Error())
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
 
 		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
 	}
 	defer tx.Rollback(ctx)
 
-	query := fmt.Sprintf(
-		"SELECT %s, %s, %s FROM %s",
-		IdColumn,
-		quoteColumn,
-		authorColumn,
-		QuoteTable,
-	)
+	authorID, err := p.authorID(ctx, tx, author)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToUpdateQuote.Error(), "error", err, "id", id)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToUpdateQuote, err)
+	}
 
-	var quotes []*storage.StorageQuote
+	query := `
+		WITH q AS (
+			UPDATE quotes SET quote = $1, author_id = $2, fingerprint = $5, version = version + 1
+			WHERE id = $3 AND version = $4 AND NOT is_deleted
+			RETURNING id, quote, author_id, version
+		)
+		SELECT ` + quoteColumns + ` FROM q JOIN authors a ON a.id = q.author_id`
+
+	fp := fingerprint.Of(quote, author)
 
-	rows, err := tx.Query(ctx, query)
+	var q storage.StorageQuote
+	err = scanQuote(tx.QueryRow(ctx, query, quote, authorID, id, version, fp), &q)
 	if err != nil {
-		p.log.Error("Failed to query quotes", "error", err)
-		return nil, fmt.Errorf("failed to query quotes: %w", err)
-	}
-	defer rows.Close()
+		if isUniqueViolation(err) {
+			return nil, p.duplicateOf(ctx, fp)
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			p.log.ErrorContext(ctx, storage.ErrFailedToUpdateQuote.Error(), "error", err, "id", id)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToUpdateQuote, err)
+		}
 
-	for rows.Next() {
-		var q storage.StorageQuote
-		if err := rows.Scan(&q.Id, &q.Text, &q.Author); err != nil {
-			p.log.Error("Failed to scan quote", "error", err)
-			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		// nothing updated: either the quote is gone or its version moved on
+		if _, err := p.Get(ctx, id); err != nil {
+			return nil, err
 		}
-		quotes = append(quotes, &q)
+
+		p.log.WarnContext(ctx, "Quote version conflict", "id", id, "version", version)
+		return nil, storage.ErrVersionConflict
 	}
 
-	tx.Commit(ctx)
+	if err := tx.Commit(ctx); err != nil {
+		p.log.ErrorContext(ctx, ErrTxCommit.Error(), "err", err.Error())
 
-	if len(quotes) == 0 {
-		p.log.Info(storage.ErrQuotesListEmpty.Error())
-		return nil, storage.ErrQuotesListEmpty
+		return nil, fmt.Errorf("%w:%w", ErrTxCommit, err)
 	}
 
-	return quotes, nil
-
+	p.log.DebugContext(ctx, "Quote updated successfully", "id", id, "version", q.Version)
+	return &q, nil
 }
 
-func (p *PostgreStorage) ListByAuthor(ctx context.Context, author string) ([]*storage.StorageQuote, error) {
-	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel: pgx.RepeatableRead,
-	})
+func (p *PostgreStorage) Get(ctx context.Context, id int) (*storage.StorageQuote, error) {
+
+	query := "SELECT " + quoteColumns + " FROM " + quotesJoin + " WHERE q.id = $1 AND NOT q.is_deleted"
+
+	var q storage.StorageQuote
+	err := scanQuote(p.conn.QueryRow(ctx, query, id), &q)
 	if err != nil {
-		p.log.Error(ErrTxBegin.Error(), "err", err.Error())
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.WarnContext(ctx, "Quote not found", "id", id)
+			return nil, storage.ErrQuoteNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetQuote.Error(), "error", err, "id", id)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
+	}
 
-		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	return &q, nil
+}
+
+func (p *PostgreStorage) QuoteOwner(ctx context.Context, id int) (string, error) {
+	var owner string
+	if err := p.conn.QueryRow(ctx, "SELECT COALESCE(owner, '') FROM quotes WHERE id = $1", id).Scan(&owner); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", storage.ErrQuoteNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetQuote.Error(), "error", err, "id", id)
+		return "", fmt.Errorf("%w: %w", storage.ErrFailedToGetQuote, err)
 	}
-	defer tx.Rollback(ctx)
 
-	query := fmt.Sprintf(
-		"SELECT %s, %s, %s FROM %s WHERE %s = $1",
-		IdColumn,
-		quoteColumn,
-		authorColumn,
-		QuoteTable,
-		authorColumn,
-	)
+	return owner, nil
+}
 
-	var quotes []*storage.StorageQuote
+func (p *PostgreStorage) ListDeleted(ctx context.Context) ([]*storage.StorageQuote, error) {
+
+	query := "SELECT " + quoteColumns + ", q.deleted_at FROM " + quotesJoin + " WHERE q.is_deleted ORDER BY q.deleted_at DESC"
 
-	rows, err := tx.Query(ctx, query, author)
+	rows, err := p.conn.Query(ctx, query)
 	if err != nil {
-		p.log.Error("Failed to query quotes", "error", err)
-		return nil, fmt.Errorf("failed to query quotes: %w", err)
+		p.log.ErrorContext(ctx, "Failed to query deleted quotes", "error", err)
+		return nil, fmt.Errorf("failed to query deleted quotes: %w", err)
 	}
 	defer rows.Close()
 
+	var quotes []*storage.StorageQuote
+
 	for rows.Next() {
 		var q storage.StorageQuote
-		if err := rows.Scan(&q.Id, &q.Text, &q.Author); err != nil {
-			p.log.Error("Failed to scan quote", "error", err)
+		if err := scanQuote(rows, &q, &q.DeletedAt); err != nil {
+			p.log.ErrorContext(ctx, "Failed to scan quote", "error", err)
 			return nil, fmt.Errorf("failed to scan quote: %w", err)
 		}
 		quotes = append(quotes, &q)
 	}
 
-	tx.Commit(ctx)
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, "Failed to read deleted quotes", "error", err)
+		return nil, fmt.Errorf("failed to read deleted quotes: %w", err)
+	}
 
 	if len(quotes) == 0 {
-		p.log.Info("No quotes found for author", "author", author)
+		p.log.InfoContext(ctx, "Trash is empty")
 		return nil, storage.ErrQuotesListEmpty
 	}
 
 	return quotes, nil
 }
 
-func (p *PostgreStorage) Random(ctx context.Context) (*models.Quote, error) {
-
-	query := fmt.Sprintf(
-		"SELECT %s, %s FROM %s ORDER BY RANDOM() LIMIT 1",
-		quoteColumn,
-		authorColumn,
-		QuoteTable,
-	)
+func (p *PostgreStorage) Restore(ctx context.Context, id int) error {
+	query := `UPDATE quotes SET is_deleted = FALSE, deleted_at = NULL WHERE id = $1 AND is_deleted;`
 
-	var quote models.Quote
-	err := p.conn.QueryRow(ctx, query).Scan(&quote.Text, &quote.Author)
+	result, err := p.conn.Exec(ctx, query, id)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, storage.ErrQuotesListEmpty
+		if isUniqueViolation(err) {
+			var fp string
+			if err := p.conn.QueryRow(ctx, "SELECT fingerprint FROM quotes WHERE id = $1", id).Scan(&fp); err != nil {
+				p.log.ErrorContext(ctx, storage.ErrFailedToRestoreQuote.Error(), "error", err, "id", id)
+				return fmt.Errorf("%w: %w", storage.ErrFailedToRestoreQuote, err)
+			}
+			return p.duplicateOf(ctx, fp)
 		}
-		p.log.Error("Failed to get random quote", "error", err)
-		return nil, fmt.Errorf("failed to get random quote: %w", err)
+		p.log.ErrorContext(ctx, storage.ErrFailedToRestoreQuote.Error(), "error", err, "id", id)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToRestoreQuote, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		p.log.WarnContext(ctx, "Quote not found in trash", "id", id)
+		return storage.ErrQuoteNotFound
 	}
 
-	return &quote, nil
+	p.log.DebugContext(ctx, "Quote restored successfully", "id", id)
+	return nil
+}
+
+func (p *PostgreStorage) Purge(ctx context.Context, before time.Time) (int, error) {
+	query := `DELETE FROM quotes WHERE is_deleted AND deleted_at < $1;`
+
+	result, err := p.conn.Exec(ctx, query, before)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToPurgeQuotes.Error(), "error", err)
+		return 0, fmt.Errorf("%w: %w", storage.ErrFailedToPurgeQuotes, err)
+	}
+
+	purged := int(result.RowsAffected())
+
+	p.log.DebugContext(ctx, "Deleted quotes purged", "count", purged, "before", before)
+	return purged, nil
 }
 
 func (p *PostgreStorage) Ping(ctx context.Context) error {
 	if err := p.conn.Ping(ctx); err != nil {
-		p.log.Error("Failed to ping database", "error", err)
+		p.log.ErrorContext(ctx, "Failed to ping database", "error", err)
 		return fmt.Errorf("%w:%w", storage.ErrPingStorage, err)
 	}
-	p.log.Debug("Database ping successful")
+	p.log.DebugContext(ctx, "Database ping successful")
 	return nil
 }
 
@@ -240,3 +344,10 @@ func (p *PostgreStorage) Close() {
 	}
 	p.log.Debug("Database connection closed")
 }
+
+// scanQuote scans a row selected with quoteColumns followed by extra columns.
+func scanQuote(row pgx.Row, q *storage.StorageQuote, extra ...any) error {
+	dest := append([]any{&q.Id, &q.Text, &q.Author, &q.Tags, &q.AuthorID, &q.Version, &q.Owner}, extra...)
+
+	return row.Scan(dest...)
+}
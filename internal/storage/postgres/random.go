@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"app/internal/domain/models"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// randomProbes is the number of random ids tried before Random falls back to
+// the first matching quote above a random id. With dense ids the first probe
+// almost always hits.
+const randomProbes = 8
+
+// Random picks a quote by probing random ids between the smallest and the
+// largest id the filter can match, the author and tag narrow the range with
+// their indexes. Every probe is a primary key lookup and a miss (a gap, a
+// deleted or a filtered out quote) is retried with a fresh id, so every
+// matching quote is equally likely.
+//
+// When the probes keep missing, Random takes the first matching quote at or
+// above a random id. That is one index scan, but favours quotes following
+// gaps: a probe hit is exactly such a pick accepted with probability 1/gap,
+// so further rejection rounds would miss as often as the probes did.
+func (p *PostgreStorage) Random(ctx context.Context, filter storage.RandomFilter) (*models.Quote, error) {
+	lo, hi, err := p.randomRange(ctx, filter)
+	if err != nil {
+		p.log.ErrorContext(ctx, "Failed to get random quote", "error", err)
+		return nil, fmt.Errorf("failed to get random quote: %w", err)
+	}
+	if lo > hi {
+		return nil, storage.ErrQuotesListEmpty
+	}
+
+	where, args := randomWhere(filter)
+
+	query := fmt.Sprintf(
+		"SELECT q.quote, a.name, %s FROM %s WHERE %s AND q.id = $%d",
+		tagsColumn,
+		quotesJoin,
+		where,
+		len(args)+1,
+	)
+
+	for i := 0; i < randomProbes; i++ {
+		id := lo + rand.IntN(hi-lo+1)
+
+		quote, err := scanRandom(p.conn.QueryRow(ctx, query, append(args, id)...))
+		if err == nil {
+			return quote, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			p.log.ErrorContext(ctx, "Failed to get random quote", "error", err)
+			return nil, fmt.Errorf("failed to get random quote: %w", err)
+		}
+	}
+
+	p.log.DebugContext(ctx, "Random id probes missed, falling back to the next id", "probes", randomProbes)
+
+	query = fmt.Sprintf(
+		"SELECT q.quote, a.name, %s FROM %s WHERE %s AND q.id >= $%d ORDER BY q.id LIMIT 1",
+		tagsColumn,
+		quotesJoin,
+		where,
+		len(args)+1,
+	)
+
+	// start over from the smallest id when nothing matches above the random one
+	for _, from := range []int{lo + rand.IntN(hi-lo+1), lo} {
+		quote, err := scanRandom(p.conn.QueryRow(ctx, query, append(args, from)...))
+		if err == nil {
+			return quote, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			p.log.ErrorContext(ctx, "Failed to get random quote", "error", err)
+			return nil, fmt.Errorf("failed to get random quote: %w", err)
+		}
+	}
+
+	return nil, storage.ErrQuotesListEmpty
+}
+
+// randomRange returns the smallest and the largest id of quotes of the
+// author with the tag, deleted and too long quotes included. lo > hi when
+// nothing matches. Every query reads the end of an index.
+func (p *PostgreStorage) randomRange(ctx context.Context, filter storage.RandomFilter) (lo int, hi int, err error) {
+	type rangeQuery struct {
+		sql string
+		arg any
+	}
+
+	queries := []rangeQuery{{sql: "SELECT min(id), max(id) FROM quotes"}}
+	if filter.Author != "" {
+		queries = append(queries, rangeQuery{
+			sql: "SELECT min(q.id), max(q.id) FROM quotes q JOIN authors a ON a.id = q.author_id WHERE a.name = $1::text",
+			arg: filter.Author,
+		})
+	}
+	if filter.Tag != "" {
+		queries = append(queries, rangeQuery{
+			sql: "SELECT min(qt.quote_id), max(qt.quote_id) FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE t.name = $1::text",
+			arg: filter.Tag,
+		})
+	}
+
+	lo, hi = math.MinInt, math.MaxInt
+	for _, q := range queries {
+		var args []any
+		if q.arg != nil {
+			args = append(args, q.arg)
+		}
+
+		var qlo, qhi *int
+		if err := p.conn.QueryRow(ctx, q.sql, args...).Scan(&qlo, &qhi); err != nil {
+			return 0, 0, err
+		}
+		if qlo == nil {
+			return 1, 0, nil
+		}
+
+		lo, hi = max(lo, *qlo), min(hi, *qhi)
+	}
+
+	return lo, hi, nil
+}
+
+// randomWhere builds the condition matching not deleted quotes passing the filter.
+func randomWhere(filter storage.RandomFilter) (string, []any) {
+	conds := []string{"NOT q.is_deleted"}
+	var args []any
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Author != "" {
+		conds = append(conds, "a.name = "+arg(filter.Author)+"::text")
+	}
+	if filter.Tag != "" {
+		conds = append(conds, "EXISTS (SELECT 1 FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE qt.quote_id = q.id AND t.name = "+arg(filter.Tag)+"::text)")
+	}
+	if filter.MaxLength > 0 {
+		conds = append(conds, "char_length(q.quote) <= "+arg(filter.MaxLength))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+func scanRandom(row pgx.Row) (*models.Quote, error) {
+	var quote models.Quote
+	if err := row.Scan(&quote.Text, &quote.Author, &quote.Tags); err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"app/internal/lib/tokenbucket"
+	"app/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// limitSweepInterval is how often TakeToken removes idle buckets.
+	limitSweepInterval = time.Minute
+	// limitIdle is how long a bucket is kept after the last request, longer
+	// than the period of any sensible limit.
+	limitIdle = 24 * time.Hour
+)
+
+// refill is the number of tokens of the stored bucket b now, $2 is the
+// capacity and $3 the tokens given back per second.
+const refill = "LEAST($2::float8, b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::float8, 0) * $3::float8)"
+
+// TakeToken implements tokenbucket.Store with buckets shared by every
+// replica, the bucket is updated by a single statement.
+func (p *PostgreStorage) TakeToken(ctx context.Context, key string, limit tokenbucket.Limit) (tokenbucket.Result, error) {
+	p.sweepLimits(ctx)
+
+	query := `
+		INSERT INTO rate_limits AS b (key, tokens, allowed, updated_at)
+		VALUES ($1, $2::float8 - 1, true, now())
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = CASE WHEN ` + refill + ` >= 1 THEN ` + refill + ` - 1 ELSE ` + refill + ` END,
+			allowed = ` + refill + ` >= 1,
+			updated_at = now()
+		RETURNING tokens, allowed`
+
+	var (
+		tokens  float64
+		allowed bool
+	)
+	if err := p.conn.QueryRow(ctx, query, key, limit.Requests, limit.Rate()).Scan(&tokens, &allowed); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToTakeToken.Error(), "error", err, "key", key)
+		return tokenbucket.Result{}, fmt.Errorf("%w: %w", storage.ErrFailedToTakeToken, err)
+	}
+
+	return tokenbucket.ResultOf(limit, tokens, allowed), nil
+}
+
+// sweepLimits removes idle buckets at most once per limitSweepInterval
+// across the goroutines of this replica.
+func (p *PostgreStorage) sweepLimits(ctx context.Context) {
+	now := time.Now().UnixNano()
+	last := p.lastLimitSweep.Load()
+	if now-last < int64(limitSweepInterval) || !p.lastLimitSweep.CompareAndSwap(last, now) {
+		return
+	}
+
+	result, err := p.conn.Exec(ctx, "DELETE FROM rate_limits WHERE updated_at < now() - make_interval(secs => $1)", limitIdle.Seconds())
+	if err != nil {
+		p.log.WarnContext(ctx, "failed to remove idle rate limit buckets", "error", err)
+		return
+	}
+
+	p.log.DebugContext(ctx, "Idle rate limit buckets removed", "count", result.RowsAffected())
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"fmt"
+)
+
+// searchConfigs maps storage search languages to text search configurations
+// and the generated tsvector columns indexed with them.
+var searchConfigs = map[string]struct {
+	config string
+	column string
+}{
+	storage.SearchEnglish: {config: "english", column: "search_en"},
+	storage.SearchRussian: {config: "russian", column: "search_ru"},
+}
+
+func (p *PostgreStorage) Search(ctx context.Context, query string, lang string, limit int) ([]*storage.SearchResult, error) {
+	cfg, ok := searchConfigs[lang]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported language %q", storage.ErrFailedToSearchQuotes, lang)
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT %[1]s,
+			ts_rank(q.%[3]s, tsq) AS rank,
+			ts_headline('%[4]s', q.quote, tsq, $3) AS headline
+		FROM %[2]s, websearch_to_tsquery('%[4]s', $1) tsq
+		WHERE NOT q.is_deleted AND q.%[3]s @@ tsq
+		ORDER BY rank DESC, q.id
+		LIMIT $2`,
+		quoteColumns,
+		quotesJoin,
+		cfg.column,
+		cfg.config,
+	)
+
+	// matches are marked with control characters and the text is escaped
+	// afterwards, the quote itself may contain HTML
+	options := "StartSel=" + storage.HeadlineStart + ", StopSel=" + storage.HeadlineStop
+
+	rows, err := p.conn.Query(ctx, sqlQuery, query, limit, options)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSearchQuotes.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToSearchQuotes, err)
+	}
+	defer rows.Close()
+
+	var results []*storage.SearchResult
+
+	for rows.Next() {
+		var r storage.SearchResult
+		if err := scanQuote(rows, &r.StorageQuote, &r.Rank, &r.Headline); err != nil {
+			p.log.ErrorContext(ctx, "Failed to scan search result", "error", err)
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		r.Headline = storage.HTMLHeadline(r.Headline)
+		results = append(results, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSearchQuotes.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToSearchQuotes, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *PostgreStorage) Similar(ctx context.Context, text string, except int, threshold float64, limit int) ([]*storage.SimilarQuote, error) {
+
+	// the threshold of the % operator is local to the transaction
+	tx, err := p.conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "SELECT set_config('pg_trgm.similarity_threshold', $1, true)", strconv.FormatFloat(threshold, 'f', -1, 64))
+	if err != nil {
+		p.log.ErrorContext(ctx, "Failed to set similarity threshold", "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToFindSimilar, err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, similarity(q.quote, $1) AS score
+		FROM %s
+		WHERE NOT q.is_deleted AND q.id <> $2 AND q.quote %% $1
+		ORDER BY score DESC, q.id
+		LIMIT $3`,
+		quoteColumns,
+		quotesJoin,
+	)
+
+	rows, err := tx.Query(ctx, query, text, except, limit)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToFindSimilar.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToFindSimilar, err)
+	}
+	defer rows.Close()
+
+	var results []*storage.SimilarQuote
+
+	for rows.Next() {
+		var r storage.SimilarQuote
+		if err := scanQuote(rows, &r.StorageQuote, &r.Score); err != nil {
+			p.log.ErrorContext(ctx, "Failed to scan similar quote", "error", err)
+			return nil, fmt.Errorf("failed to scan similar quote: %w", err)
+		}
+		results = append(results, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToFindSimilar.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToFindSimilar, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"app/internal/domain/models"
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *PostgreStorage) ListByTags(ctx context.Context, tags storage.TagFilter, page storage.Page) (*storage.QuotesPage, error) {
+	q, err := tagsQuery(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.listPage(ctx, q, page)
+}
+
+// tagsQuery builds the filter of quotes by tags.
+func tagsQuery(tags storage.TagFilter) (listQuery, error) {
+	matched := "SELECT COUNT(*) FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE qt.quote_id = q.id AND t.name = ANY($1::text[])"
+
+	var q listQuery
+
+	switch tags.Match {
+	case storage.TagsAny:
+		q.where = "(" + matched + ") > 0"
+		q.args = []any{tags.Tags}
+
+	case storage.TagsAll:
+		q.where = "(" + matched + ") = $2"
+		q.args = []any{tags.Tags, len(tags.Tags)}
+
+	default:
+		return q, fmt.Errorf("%w: unknown tag match mode %q", storage.ErrFailedToListQuotes, tags.Match)
+	}
+
+	return q, nil
+}
+
+func (p *PostgreStorage) AddTags(ctx context.Context, id int, tags []string) (*storage.StorageQuote, error) {
+	return p.changeTags(ctx, id, func(tx pgx.Tx) (int64, error) {
+		return p.tagQuote(ctx, tx, id, tags)
+	})
+}
+
+func (p *PostgreStorage) RemoveTags(ctx context.Context, id int, tags []string) (*storage.StorageQuote, error) {
+	return p.changeTags(ctx, id, func(tx pgx.Tx) (int64, error) {
+		query := `
+			DELETE FROM quote_tags qt
+			USING tags t
+			WHERE t.id = qt.tag_id AND qt.quote_id = $1 AND t.name = ANY($2::text[])`
+
+		result, err := tx.Exec(ctx, query, id, tags)
+		if err != nil {
+			return 0, err
+		}
+
+		return result.RowsAffected(), nil
+	})
+}
+
+func (p *PostgreStorage) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	query := `
+		SELECT t.name, COUNT(*) AS quotes_count
+		FROM tags t
+		JOIN quote_tags qt ON qt.tag_id = t.id
+		JOIN quotes q ON q.id = qt.quote_id
+		WHERE NOT q.is_deleted
+		GROUP BY t.name
+		ORDER BY quotes_count DESC, t.name`
+
+	rows, err := p.conn.Query(ctx, query)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListTags.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListTags, err)
+	}
+	defer rows.Close()
+
+	var tags []*models.Tag
+
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.Name, &t.QuotesCount); err != nil {
+			p.log.ErrorContext(ctx, "Failed to scan tag", "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListTags, err)
+		}
+		tags = append(tags, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListTags.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListTags, err)
+	}
+
+	return tags, nil
+}
+
+// changeTags runs change against the locked quote row and bumps the quote
+// version when change reports affected rows.
+func (p *PostgreStorage) changeTags(ctx context.Context, id int, change func(tx pgx.Tx) (int64, error)) (*storage.StorageQuote, error) {
+
+	tx, err := p.conn.Begin(ctx)
+	if err != nil {
+		p.log.ErrorContext(ctx, ErrTxBegin.Error(), "err", err.Error())
+
+		return nil, fmt.Errorf("%w:%w", ErrTxBegin, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked int
+	err = tx.QueryRow(ctx, "SELECT id FROM quotes WHERE id = $1 AND NOT is_deleted FOR UPDATE", id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.WarnContext(ctx, "Quote not found", "id", id)
+			return nil, storage.ErrQuoteNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToTagQuote.Error(), "error", err, "id", id)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToTagQuote, err)
+	}
+
+	changed, err := change(tx)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToTagQuote.Error(), "error", err, "id", id)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToTagQuote, err)
+	}
+
+	if changed > 0 {
+		if _, err := tx.Exec(ctx, "UPDATE quotes SET version = version + 1 WHERE id = $1", id); err != nil {
+			p.log.ErrorContext(ctx, storage.ErrFailedToTagQuote.Error(), "error", err, "id", id)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToTagQuote, err)
+		}
+	}
+
+	query := "SELECT " + quoteColumns + " FROM " + quotesJoin + " WHERE q.id = $1"
+
+	var q storage.StorageQuote
+	if err := scanQuote(tx.QueryRow(ctx, query, id), &q); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToTagQuote.Error(), "error", err, "id", id)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToTagQuote, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.log.ErrorContext(ctx, ErrTxCommit.Error(), "err", err.Error())
+
+		return nil, fmt.Errorf("%w:%w", ErrTxCommit, err)
+	}
+
+	p.log.DebugContext(ctx, "Quote tags changed successfully", "id", id, "tags", q.Tags, "version", q.Version)
+	return &q, nil
+}
+
+// tagQuote attaches tags to the quote, creating missing tags, and returns
+// the number of newly attached ones.
+func (p *PostgreStorage) tagQuote(ctx context.Context, q querier, quoteID int, tags []string) (int64, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		WITH t AS (
+			INSERT INTO tags (name) SELECT unnest($2::text[])
+			ON CONFLICT (name) DO UPDATE SET name = tags.name
+			RETURNING id
+		)
+		INSERT INTO quote_tags (quote_id, tag_id)
+		SELECT $1, id FROM t
+		ON CONFLICT DO NOTHING`
+
+	result, err := q.Exec(ctx, query, quoteID, tags)
+	if err != nil {
+		return 0, fmt.Errorf("failed to tag quote %d: %w", quoteID, err)
+	}
+
+	return result.RowsAffected(), nil
+}
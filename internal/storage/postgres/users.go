@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"app/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const userColumns = "id, name, role, created_at"
+
+func scanUser(row pgx.Row, u *storage.User) error {
+	return row.Scan(&u.ID, &u.Name, &u.Role, &u.CreatedAt)
+}
+
+func (p *PostgreStorage) SaveUser(ctx context.Context, user *storage.User) (int, error) {
+	var id int
+	err := p.conn.QueryRow(ctx, "INSERT INTO users (name, role) VALUES ($1, $2) RETURNING id", user.Name, user.Role).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, storage.ErrUserExists
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveUser.Error(), "error", err, "name", user.Name)
+		return 0, fmt.Errorf("%w: %w", storage.ErrFailedToSaveUser, err)
+	}
+
+	p.log.DebugContext(ctx, "User saved", "id", id, "name", user.Name, "role", user.Role)
+	return id, nil
+}
+
+func (p *PostgreStorage) GetUser(ctx context.Context, id int) (*storage.User, error) {
+	return p.user(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id)
+}
+
+func (p *PostgreStorage) GetUserByName(ctx context.Context, name string) (*storage.User, error) {
+	return p.user(ctx, "SELECT "+userColumns+" FROM users WHERE name = $1", name)
+}
+
+func (p *PostgreStorage) user(ctx context.Context, query string, arg any) (*storage.User, error) {
+	var u storage.User
+	if err := scanUser(p.conn.QueryRow(ctx, query, arg), &u); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
+		}
+		p.log.ErrorContext(ctx, storage.ErrFailedToGetUser.Error(), "error", err, "user", arg)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToGetUser, err)
+	}
+
+	return &u, nil
+}
+
+func (p *PostgreStorage) ListUsers(ctx context.Context) ([]*storage.User, error) {
+	rows, err := p.conn.Query(ctx, "SELECT "+userColumns+" FROM users ORDER BY id")
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListUsers.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListUsers, err)
+	}
+	defer rows.Close()
+
+	var users []*storage.User
+	for rows.Next() {
+		var u storage.User
+		if err := scanUser(rows, &u); err != nil {
+			p.log.ErrorContext(ctx, storage.ErrFailedToListUsers.Error(), "error", err)
+			return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListUsers, err)
+		}
+		users = append(users, &u)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToListUsers.Error(), "error", err)
+		return nil, fmt.Errorf("%w: %w", storage.ErrFailedToListUsers, err)
+	}
+
+	return users, nil
+}
+
+func (p *PostgreStorage) SetUserRole(ctx context.Context, id int, role string) error {
+	result, err := p.conn.Exec(ctx, "UPDATE users SET role = $2 WHERE id = $1", id, role)
+	if err != nil {
+		p.log.ErrorContext(ctx, storage.ErrFailedToSaveUser.Error(), "error", err, "id", id)
+		return fmt.Errorf("%w: %w", storage.ErrFailedToSaveUser, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	p.log.DebugContext(ctx, "User role changed", "id", id, "role", role)
+	return nil
+}
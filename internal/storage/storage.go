@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"app/internal/domain/errcode"
 	"app/internal/domain/models"
 	"context"
 	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
 )
 
 var (
@@ -11,27 +16,293 @@ var (
 	ErrInvalidQuote   = errors.New("invalid quote")
 	ErrPingStorage    = errors.New("failed to ping storage")
 
-	ErrQuoteNotFound = errors.New("quote not found")
+	ErrQuoteNotFound   = errcode.New(errcode.QuoteNotFound, "quote not found")
+	ErrAuthorNotFound  = errcode.New(errcode.AuthorNotFound, "author not found")
+	ErrVersionConflict = errcode.New(errcode.VersionConflict, "quote version conflict")
+	ErrPinNotFound     = errcode.New(errcode.PinNotFound, "daily pin not found")
+	ErrDuplicateQuote  = errcode.New(errcode.DuplicateQuote, "duplicate quote")
 
 	ErrFailedToSaveQuote    = errors.New("failed to save quote")
 	ErrFailedToDeleteQuote  = errors.New("failed to delete quote")
+	ErrFailedToUpdateQuote  = errors.New("failed to update quote")
+	ErrFailedToRestoreQuote = errors.New("failed to restore quote")
+	ErrFailedToPurgeQuotes  = errors.New("failed to purge deleted quotes")
 	ErrFailedToGetQuote     = errors.New("failed to get quote")
 	ErrFailedToListQuotes   = errors.New("failed to list quotes")
 	ErrFailedToListByAuthor = errors.New("failed to list quotes by author")
-	ErrQuotesListEmpty      = errors.New("quotes list is empty")
+	ErrFailedToSearchQuotes = errors.New("failed to search quotes")
+	ErrFailedToFindSimilar  = errors.New("failed to find similar quotes")
+	ErrFailedToListAuthors  = errors.New("failed to list authors")
+	ErrFailedToGetAuthor    = errors.New("failed to get author")
+	ErrFailedToTagQuote     = errors.New("failed to update quote tags")
+	ErrFailedToListTags     = errors.New("failed to list tags")
+	ErrFailedToPickQuote    = errors.New("failed to pick quote")
+	ErrFailedToPinQuote     = errors.New("failed to pin daily quote")
+	ErrFailedToImportQuotes = errors.New("failed to import quotes")
+	ErrFailedToExportQuotes = errors.New("failed to export quotes")
+	ErrFailedToSaveAPIKey   = errors.New("failed to save api key")
+	ErrFailedToGetAPIKey    = errors.New("failed to get api key")
+	ErrFailedToListAPIKeys  = errors.New("failed to list api keys")
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrFailedToSaveUser     = errors.New("failed to save user")
+	ErrFailedToGetUser      = errors.New("failed to get user")
+	ErrFailedToListUsers    = errors.New("failed to list users")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUserExists           = errors.New("user already exists")
+	ErrFailedToTakeToken    = errors.New("failed to take rate limit token")
+	ErrQuotesListEmpty      = errcode.New(errcode.NoQuotes, "quotes list is empty")
 )
 
+// DuplicateError is returned when a not deleted quote with the same
+// fingerprint (see package fingerprint) already exists. It matches
+// ErrDuplicateQuote with errors.Is.
+type DuplicateError struct {
+	ExistingID int
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: quote %d already exists", ErrDuplicateQuote, e.ExistingID)
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicateQuote
+}
+
+// ImportDuplicatesError lists the quotes of an import that were found to
+// duplicate stored quotes while storing them, by index into the imported
+// quotes. It matches ErrDuplicateQuote with errors.Is.
+type ImportDuplicatesError struct {
+	Duplicates map[int]*DuplicateError
+}
+
+func (e *ImportDuplicatesError) Error() string {
+	return fmt.Sprintf("%s: %d imported quotes already exist", ErrDuplicateQuote, len(e.Duplicates))
+}
+
+func (e *ImportDuplicatesError) Is(target error) bool {
+	return target == ErrDuplicateQuote
+}
+
 type StorageQuote struct {
 	models.Quote
-	Id int `json:"id"`
+	Id        int        `json:"id"`
+	AuthorID  int        `json:"author_id"`
+	Version   int        `json:"version"`
+	Owner     string     `json:"owner,omitempty"` // subject of the submitter, see package access
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Page selects a slice of a quotes list (keyset pagination). Plain lists are
+// ordered by id, ranked lists by rank descending and then by id.
+type Page struct {
+	Limit     int     // maximum number of quotes to return
+	AfterID   int     // id of the last quote of the previous page
+	AfterRank float64 // rank of the last quote of the previous page, ranked lists only
+	WithTotal bool    // count every quote matching the filter, ignoring the page
+}
+
+type QuotesPage struct {
+	Quotes   []*StorageQuote
+	HasMore  bool
+	LastRank float64 // rank of the last returned quote, ranked lists only
+	Total    *int    // set only when Page.WithTotal is requested
+}
+
+type AuthorsPage struct {
+	Authors []*models.Author
+	HasMore bool
+	Total   *int // set only when Page.WithTotal is requested
+}
+
+// Author match modes supported by ListByAuthor.
+const (
+	MatchExact  = "exact"  // byte for byte equal names
+	MatchICase  = "icase"  // case-insensitive equality, exact case ranked first
+	MatchPrefix = "prefix" // case-insensitive prefix, shorter names ranked first
+	MatchFuzzy  = "fuzzy"  // trigram similarity above Threshold, most similar first
+)
+
+type AuthorFilter struct {
+	Name      string
+	Match     string
+	Threshold float64 // minimal similarity for MatchFuzzy, between 0 and 1
+}
+
+// Tag match modes supported by ListByTags.
+const (
+	TagsAny = "any" // quotes having at least one of the tags
+	TagsAll = "all" // quotes having every tag
+)
+
+type TagFilter struct {
+	Tags  []string // normalized and without duplicates
+	Match string
+}
+
+// ExportFilter selects exported quotes. At most one of Author and Tags is set,
+// none means every quote.
+type ExportFilter struct {
+	Author *AuthorFilter
+	Tags   *TagFilter
+}
+
+// RandomFilter restricts Random to matching quotes, zero fields match everything.
+type RandomFilter struct {
+	Author    string // exact author name
+	Tag       string // normalized tag name
+	MaxLength int    // maximum quote length in characters
+}
+
+// Text search configurations supported by Search.
+const (
+	SearchEnglish = "en"
+	SearchRussian = "ru"
+)
+
+type SearchResult struct {
+	StorageQuote
+	Rank     float64 `json:"rank"`
+	Headline string  `json:"headline"` // HTML-escaped quote text with matches wrapped in <b></b>
+}
+
+// Stores mark matches in headlines with these control characters, which
+// quotes cannot contain, and pass them to HTMLHeadline.
+const (
+	HeadlineStart = "\x02"
+	HeadlineStop  = "\x03"
+)
+
+var headlineMarkers = strings.NewReplacer(HeadlineStart, "<b>", HeadlineStop, "</b>")
+
+// HTMLHeadline escapes the quote text of a headline and turns the match
+// markers into <b></b>, so only the markers are HTML.
+func HTMLHeadline(marked string) string {
+	return headlineMarkers.Replace(html.EscapeString(marked))
+}
+
+// SimilarQuote is a quote found by Similar with its trigram similarity to
+// the compared text, from 0 to 1.
+type SimilarQuote struct {
+	StorageQuote
+	Score float64 `json:"score"`
+}
+
+// APIKey is a stored API key. The key itself is never stored, only its
+// SHA-256 Hash and a Prefix that tells keys apart in listings.
+type APIKey struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"-"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// User is a person or a service API keys are issued for. Its Name is the
+// subject recorded as the owner of quotes, Role one of the access roles.
+type User struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Storage interface {
-	Save(ctx context.Context, quote string, author string) (int, error)
+	// Save, Update, Restore and Import return *DuplicateError instead of
+	// creating a second not deleted quote with the same fingerprint.
+	// Owner is the subject of the submitter, empty when unknown.
+	Save(ctx context.Context, quote string, author string, tags []string, owner string) (int, error)
+	// Import bulk inserts validated quotes and returns their ids in order.
+	// Atomic imports insert everything or nothing. Otherwise quotes are
+	// committed in batches and quotes of failed batches get id 0, the error
+	// then describes the failed batches. Quotes duplicating stored ones are
+	// skipped with id 0 and listed in an *ImportDuplicatesError, atomic
+	// imports then store nothing.
+	Import(ctx context.Context, quotes []*models.Quote, owner string, atomic bool) ([]int, error)
+	// QuoteOwner returns the owner of a quote, deleted or not, or ErrQuoteNotFound.
+	QuoteOwner(ctx context.Context, id int) (string, error)
+	// FindByFingerprints returns ids of not deleted quotes by fingerprint,
+	// fingerprints without a quote are missing from the map.
+	FindByFingerprints(ctx context.Context, fingerprints []string) (map[string]int, error)
+	// Delete moves the quote to the trash. Deleted quotes are hidden from
+	// every read method except ListDeleted until they are restored or purged.
 	Delete(ctx context.Context, id int) error
-	Get(ctx context.Context, id int) (*models.Quote, error)
-	List(ctx context.Context) ([]*StorageQuote, error)
-	ListByAuthor(ctx context.Context, author string) ([]*StorageQuote, error)
-	Random(ctx context.Context) (*models.Quote, error)
+	ListDeleted(ctx context.Context) ([]*StorageQuote, error)
+	Restore(ctx context.Context, id int) error
+	// Purge permanently removes quotes deleted before the given time and returns their count.
+	Purge(ctx context.Context, before time.Time) (int, error)
+	// Update replaces quote text and author, keeping its tags, if the stored version still equals version.
+	// It returns ErrVersionConflict when the quote was modified in the meantime.
+	Update(ctx context.Context, id int, quote string, author string, version int) (*StorageQuote, error)
+	Get(ctx context.Context, id int) (*StorageQuote, error)
+	List(ctx context.Context, page Page) (*QuotesPage, error)
+	// ListByAuthor lists quotes of matching authors. Every match mode except
+	// MatchExact returns a ranked list.
+	ListByAuthor(ctx context.Context, author AuthorFilter, page Page) (*QuotesPage, error)
+	ListByAuthorID(ctx context.Context, authorID int, page Page) (*QuotesPage, error)
+	ListByTags(ctx context.Context, tags TagFilter, page Page) (*QuotesPage, error)
+
+	// AddTags and RemoveTags change the tags of a not deleted quote and return it.
+	// The version is bumped only when the set of tags actually changes.
+	AddTags(ctx context.Context, id int, tags []string) (*StorageQuote, error)
+	RemoveTags(ctx context.Context, id int, tags []string) (*StorageQuote, error)
+	// ListTags lists tags used by not deleted quotes, most used first.
+	ListTags(ctx context.Context) ([]*models.Tag, error)
+
+	// ListAuthors lists authors having at least one not deleted quote, ordered by id.
+	ListAuthors(ctx context.Context, page Page) (*AuthorsPage, error)
+	GetAuthor(ctx context.Context, id int) (*models.Author, error)
+	// Random returns a random not deleted quote matching the filter or
+	// ErrQuotesListEmpty when there is none. The choice is uniform unless
+	// matching ids are too sparse to probe, see the postgres storage.
+	Random(ctx context.Context, filter RandomFilter) (*models.Quote, error)
+	// Export calls fn for every not deleted quote matching the filter in id
+	// order, without loading them all at once. An error returned by fn stops
+	// the export and is returned as is.
+	Export(ctx context.Context, filter ExportFilter, fn func(q *StorageQuote) error) error
+
+	// Pick returns the not deleted quote at the position chosen by pick, which
+	// gets the number of such quotes and returns a position in [0, count).
+	// Quotes are ordered by id. It returns ErrQuotesListEmpty when there are no quotes.
+	Pick(ctx context.Context, pick func(count int) int) (*StorageQuote, error)
+
+	// Daily pins override the quote of the day for a date, days are truncated to dates.
+	PinDaily(ctx context.Context, day time.Time, quoteID int) error
+	UnpinDaily(ctx context.Context, day time.Time) error
+	// DailyPin returns the quote pinned for the day or ErrPinNotFound. Pins of
+	// deleted quotes are ignored.
+	DailyPin(ctx context.Context, day time.Time) (*StorageQuote, error)
+
+	// Search finds quotes whose text matches the web search style query,
+	// best matches first. lang is one of SearchEnglish or SearchRussian.
+	Search(ctx context.Context, query string, lang string, limit int) ([]*SearchResult, error)
+	// Similar returns up to limit not deleted quotes, other than the quote
+	// except, whose text scores at least threshold against text, best first.
+	// Postgres scores with pg_trgm similarity, the memory storage estimates
+	// the same trigram similarity with MinHash.
+	Similar(ctx context.Context, text string, except int, threshold float64, limit int) ([]*SimilarQuote, error)
+
+	// SaveAPIKey stores a new key and returns its id, CreatedAt is set by the storage.
+	SaveAPIKey(ctx context.Context, key *APIKey) (int, error)
+	// APIKeyByHash returns the key with the hash, revoked and expired keys
+	// included, or ErrAPIKeyNotFound.
+	APIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	// ListAPIKeys lists every key ordered by id.
+	ListAPIKeys(ctx context.Context) ([]*APIKey, error)
+	// RevokeAPIKey revokes a key. It returns ErrAPIKeyNotFound for unknown
+	// and already revoked keys.
+	RevokeAPIKey(ctx context.Context, id int) error
+
+	// SaveUser stores a new user and returns its id or ErrUserExists.
+	SaveUser(ctx context.Context, user *User) (int, error)
+	// GetUser and GetUserByName return ErrUserNotFound for unknown users.
+	GetUser(ctx context.Context, id int) (*User, error)
+	GetUserByName(ctx context.Context, name string) (*User, error)
+	// ListUsers lists every user ordered by id.
+	ListUsers(ctx context.Context) ([]*User, error)
+	SetUserRole(ctx context.Context, id int, role string) error
 	Close()
 }
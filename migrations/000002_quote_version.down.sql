@@ -0,0 +1 @@
+ALTER TABLE quotes DROP COLUMN version;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS quotes_deleted_at_index;
+
+ALTER TABLE quotes
+    DROP COLUMN deleted_at,
+    DROP COLUMN is_deleted;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE quotes
+    ADD COLUMN is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX quotes_deleted_at_index ON quotes (deleted_at) WHERE is_deleted;
\ No newline at end of file
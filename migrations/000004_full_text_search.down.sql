@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS quotes_search_ru_index;
+DROP INDEX IF EXISTS quotes_search_en_index;
+
+ALTER TABLE quotes
+    DROP COLUMN search_ru,
+    DROP COLUMN search_en;
\ No newline at end of file
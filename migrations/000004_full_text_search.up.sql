@@ -0,0 +1,6 @@
+ALTER TABLE quotes
+    ADD COLUMN search_en TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', quote)) STORED,
+    ADD COLUMN search_ru TSVECTOR GENERATED ALWAYS AS (to_tsvector('russian', quote)) STORED;
+
+CREATE INDEX quotes_search_en_index ON quotes USING GIN (search_en);
+CREATE INDEX quotes_search_ru_index ON quotes USING GIN (search_ru);
\ No newline at end of file
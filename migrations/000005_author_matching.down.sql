@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS quotes_author_trgm_index;
+DROP INDEX IF EXISTS quotes_author_lower_index;
\ No newline at end of file
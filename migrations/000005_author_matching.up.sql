@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX quotes_author_lower_index ON quotes (lower(author) text_pattern_ops);
+CREATE INDEX quotes_author_trgm_index ON quotes USING GIN (lower(author) gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1,15 @@
+ALTER TABLE quotes ADD COLUMN author TEXT;
+
+UPDATE quotes q
+SET author = a.name
+FROM authors a
+WHERE a.id = q.author_id;
+
+ALTER TABLE quotes ALTER COLUMN author SET NOT NULL;
+
+CREATE INDEX quotes_author_lower_index ON quotes (lower(author) text_pattern_ops);
+CREATE INDEX quotes_author_trgm_index ON quotes USING GIN (lower(author) gin_trgm_ops);
+
+ALTER TABLE quotes DROP COLUMN author_id;
+
+DROP TABLE authors;
\ No newline at end of file
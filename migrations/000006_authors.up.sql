@@ -0,0 +1,29 @@
+CREATE TABLE authors(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX authors_name_pattern_index ON authors (lower(name) text_pattern_ops);
+CREATE INDEX authors_name_trgm_index ON authors USING GIN (lower(name) gin_trgm_ops);
+
+-- Deduplicate free-text authors: trimmed and inner whitespace collapsed,
+-- the same way the service normalizes new authors.
+INSERT INTO authors (name)
+SELECT DISTINCT btrim(regexp_replace(author, '\s+', ' ', 'g'))
+FROM quotes;
+
+ALTER TABLE quotes ADD COLUMN author_id INTEGER REFERENCES authors (id);
+
+UPDATE quotes q
+SET author_id = a.id
+FROM authors a
+WHERE a.name = btrim(regexp_replace(q.author, '\s+', ' ', 'g'));
+
+ALTER TABLE quotes ALTER COLUMN author_id SET NOT NULL;
+
+CREATE INDEX quotes_author_id_index ON quotes (author_id, id);
+
+DROP INDEX IF EXISTS quotes_author_trgm_index;
+DROP INDEX IF EXISTS quotes_author_lower_index;
+
+ALTER TABLE quotes DROP COLUMN author;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE quote_tags;
+
+DROP TABLE tags;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE daily_pins;
\ No newline at end of file
@@ -0,0 +1,4 @@
+CREATE TABLE daily_pins(
+    day DATE PRIMARY KEY,
+    quote_id INTEGER NOT NULL REFERENCES quotes (id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS quotes_fingerprint_uindex;
+
+ALTER TABLE quotes DROP COLUMN fingerprint;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Fingerprints are computed by the application (package fingerprint).
+-- Existing rows are left without one until cmd/dedupe merges their duplicates
+-- and fills the column.
+ALTER TABLE quotes ADD COLUMN fingerprint TEXT;
+
+CREATE UNIQUE INDEX quotes_fingerprint_uindex ON quotes (fingerprint) WHERE NOT is_deleted;
\ No newline at end of file
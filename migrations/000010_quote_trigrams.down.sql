@@ -0,0 +1 @@
+DROP INDEX IF EXISTS quotes_quote_trgm_index;
\ No newline at end of file
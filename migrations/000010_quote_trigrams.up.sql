@@ -0,0 +1 @@
+CREATE INDEX quotes_quote_trgm_index ON quotes USING GIN (quote gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE quotes DROP COLUMN owner;
+ALTER TABLE api_keys DROP COLUMN user_id;
+DROP TABLE users;
\ No newline at end of file
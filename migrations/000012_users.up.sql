@@ -0,0 +1,16 @@
+CREATE TABLE users(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    role TEXT NOT NULL CHECK (role IN ('reader', 'contributor', 'editor', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- keys issued before users existed belong to an admin and keep working as before
+INSERT INTO users (name, role) SELECT 'admin', 'admin' WHERE EXISTS (SELECT 1 FROM api_keys);
+
+ALTER TABLE api_keys ADD COLUMN user_id INTEGER REFERENCES users (id) ON DELETE CASCADE;
+UPDATE api_keys SET user_id = (SELECT id FROM users WHERE name = 'admin');
+ALTER TABLE api_keys ALTER COLUMN user_id SET NOT NULL;
+
+-- subject of the submitter, a user name or the sub claim of a JWT
+ALTER TABLE quotes ADD COLUMN owner TEXT;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- token buckets of the rate limiter shared by every replica, losing them on
+-- a crash only resets the limits
+CREATE UNLOGGED TABLE rate_limits(
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX rate_limits_updated_at_idx ON rate_limits (updated_at);
\ No newline at end of file